@@ -62,7 +62,11 @@ var articleCmd = &cobra.Command{
 			Identifier:    articleIdentifier,
 		}
 
-		return nip23.PublishArticle(context.Background(), profile, sk, opts)
+		ev, results, err := nip23.PublishArticle(context.Background(), profile, sk, opts, publishPolicy(profile, sk))
+		if reportErr := reportPublish(ev, results); reportErr != nil {
+			return reportErr
+		}
+		return err
 	},
 }
 
@@ -73,4 +77,6 @@ func init() {
 	articleCmd.Flags().StringVar(&articlePublished, "published-at", "", "Custom published-at timestamp")
 	articleCmd.Flags().StringVar(&articleIdentifier, "identifier", "", "Stable identifier for the d tag")
 	registerProfileFlag(articleCmd)
+	registerPublishFlags(articleCmd)
+	registerPublishJSONFlag(articleCmd)
 }
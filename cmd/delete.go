@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/spf13/cobra"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip09"
+	"nostr-cli/nips/nip23"
+	nostrkeys "nostr-cli/nostr"
+)
+
+var (
+	deleteReason   string
+	deleteArticles stringListFlag
+	deleteVerify   bool
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <event>...",
+	Short: "Ask relays to delete your events (NIP-09)",
+	Long:  "Publish a Kind 5 deletion request for notes or articles you wrote. Event ids, note and nevent references delete that exact event (for example one old article revision); naddr references and --article delete the article by its address, covering every version so far. With --verify, each relay is queried again afterwards to see whether it still serves the events.",
+	Args:  cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && len(deleteArticles) == 0 {
+			_ = cmd.Help()
+			return errors.New("expected at least one event reference or --article")
+		}
+
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		auth := relayAuth(profile, "")
+
+		var targets []nip09.Target
+		var hints []string
+		for _, value := range args {
+			ref, err := resolveEventRef(value)
+			if err != nil {
+				return err
+			}
+			ev, hint, err := fetchReferencedEvent(ctx, profile.Relays, value, auth)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, nip09.Target{Event: ev, ByAddress: ref.Type == "naddr"})
+			if hint != "" {
+				hints = append(hints, hint)
+			}
+		}
+		for _, identifier := range deleteArticles {
+			ev, err := nip23.FetchArticle(ctx, profile.Relays, profile.PublicKey, identifier, auth)
+			if err != nil {
+				return err
+			}
+			targets = append(targets, nip09.Target{Event: ev, ByAddress: true})
+		}
+		if err := nip09.CheckAuthor(targets, profile.PublicKey); err != nil {
+			return fmt.Errorf("refusing to delete: %w", err)
+		}
+
+		sk, err := nostrkeys.PromptForDecryptedKey(profile)
+		if err != nil {
+			return err
+		}
+		ev, results, err := nip09.PublishDeletion(ctx, profile, sk, targets, deleteReason, withRelayHints(nil, hints), publishPolicy(profile, sk))
+		if ev == nil {
+			return err
+		}
+		if reportErr := reportPublish(ev, results); reportErr != nil {
+			return reportErr
+		}
+		if deleteVerify {
+			verifyDeletion(ctx, ev, targets, results, auth)
+		}
+		return err
+	},
+}
+
+func init() {
+	deleteCmd.Flags().StringVar(&deleteReason, "reason", "", "Why the events are being deleted")
+	deleteCmd.Flags().Var(&deleteArticles, "article", "Delete your article with this identifier (repeatable)")
+	deleteCmd.Flags().BoolVar(&deleteVerify, "verify", false, "Query the relays afterwards and report which still serve the events")
+	registerProfileFlag(deleteCmd)
+	registerPublishFlags(deleteCmd)
+}
+
+func verifyDeletion(ctx context.Context, deletion *nostrlib.Event, targets []nip09.Target, results []relay.PublishResult, auth *relay.Auth) {
+	fmt.Println("Verifying deletion:")
+	for _, result := range results {
+		remaining, err := nip09.Remaining(ctx, result.Relay, deletion, targets, auth)
+		switch {
+		case err != nil:
+			fmt.Printf("  %s: could not check: %v\n", result.Relay, err)
+		case len(remaining) > 0:
+			fmt.Printf("  %s: still serves %s\n", result.Relay, strings.Join(remaining, ", "))
+		default:
+			fmt.Printf("  %s: deleted\n", result.Relay)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip00"
+	nostrkeys "nostr-cli/nostr"
+)
+
+// resolveDisplayNames looks up Kind 0 names for pubKeys, a few at a time.
+func resolveDisplayNames(ctx context.Context, relays []string, pubKeys []string, auth *relay.Auth) map[string]string {
+	names := make(map[string]string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, 8)
+
+	seen := make(map[string]struct{})
+	for _, pubKey := range pubKeys {
+		if _, ok := seen[pubKey]; ok {
+			continue
+		}
+		seen[pubKey] = struct{}{}
+
+		wg.Add(1)
+		go func(pubKey string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			metadata, err := nip00.FetchProfile(ctx, relays, pubKey, auth)
+			if err != nil || metadata == nil {
+				return
+			}
+			name := strings.TrimSpace(metadata.DisplayName)
+			if name == "" {
+				name = strings.TrimSpace(metadata.Name)
+			}
+			if name == "" {
+				return
+			}
+			mu.Lock()
+			names[pubKey] = name
+			mu.Unlock()
+		}(pubKey)
+	}
+	wg.Wait()
+	return names
+}
+
+func authorLabel(names map[string]string, pubKey string) string {
+	if name := names[pubKey]; name != "" {
+		return name
+	}
+	npub, err := nostrkeys.HexToNpub(pubKey)
+	if err != nil {
+		return shortID(pubKey)
+	}
+	return npub[:12] + "…"
+}
+
+func shortID(id string) string {
+	if len(id) <= 8 {
+		return id
+	}
+	return id[:8]
+}
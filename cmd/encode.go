@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	nostrkeys "nostr-cli/nostr"
+)
+
+var (
+	encodeRelays     stringListFlag
+	encodeAuthor     string
+	encodeEventKind  int
+	encodeAddrKind   int
+	encodeIdentifier string
+	decodeJSON       bool
+)
+
+var encodeCmd = &cobra.Command{
+	Use:   "encode",
+	Short: "Build NIP-19 npub, note, nprofile, nevent, or naddr strings",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var encodeNpubCmd = &cobra.Command{
+	Use:   "npub <pubkey>",
+	Short: "Encode a public key as npub",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pubKey, _, err := resolvePubKey(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+		return printEncoded(nostrkeys.HexToNpub(pubKey))
+	},
+}
+
+var encodeNoteCmd = &cobra.Command{
+	Use:   "note <event-id>",
+	Short: "Encode an event id as note",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref, err := resolveEventID(args[0])
+		if err != nil {
+			return err
+		}
+		return printEncoded(nostrkeys.HexToNote(ref.EventID))
+	},
+}
+
+var encodeNprofileCmd = &cobra.Command{
+	Use:   "nprofile <pubkey>",
+	Short: "Encode a public key with relay hints",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pubKey, _, err := resolvePubKey(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+		return printEncoded(nostrkeys.EncodeProfile(pubKey, encodeRelays))
+	},
+}
+
+var encodeNeventCmd = &cobra.Command{
+	Use:   "nevent <event-id>",
+	Short: "Encode an event id with relay, author, and kind hints",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref, err := resolveEventID(args[0])
+		if err != nil {
+			return err
+		}
+		author := ""
+		if encodeAuthor != "" {
+			if author, _, err = resolvePubKey(context.Background(), encodeAuthor); err != nil {
+				return err
+			}
+		}
+		return printEncoded(nostrkeys.EncodeEvent(ref.EventID, encodeRelays, author, encodeEventKind))
+	},
+}
+
+var encodeNaddrCmd = &cobra.Command{
+	Use:   "naddr",
+	Short: "Encode an addressable event such as a NIP-23 article",
+	Long:  "Build an naddr from --identifier (the d tag), --kind (defaults to 30023 articles), and --author (defaults to your configured key), plus optional --relay hints.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if encodeIdentifier == "" {
+			return fmt.Errorf("--identifier is required")
+		}
+		author := encodeAuthor
+		if author == "" {
+			_, profile, _, err := loadProfileForCommand()
+			if err != nil {
+				return err
+			}
+			author = profile.PublicKey
+		} else {
+			var err error
+			if author, _, err = resolvePubKey(context.Background(), author); err != nil {
+				return err
+			}
+		}
+		return printEncoded(nostrkeys.EncodeAddress(author, encodeAddrKind, encodeIdentifier, encodeRelays))
+	},
+}
+
+var decodeCmd = &cobra.Command{
+	Use:   "decode <bech32>",
+	Short: "Decode a NIP-19 string or nostr: URI",
+	Long:  "Print the hex value and TLV fields (relays, author, kind, identifier) of an npub, nsec, note, nprofile, nevent, or naddr.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref, err := nostrkeys.DecodeReference(args[0])
+		if err != nil {
+			return err
+		}
+		if decodeJSON {
+			output, err := json.MarshalIndent(ref, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+
+		fmt.Printf("type:       %s\n", ref.Type)
+		switch ref.Type {
+		case "hex":
+			fmt.Printf("hex:        %s\n", ref.EventID)
+		case "nsec":
+			fmt.Printf("secret key: %s\n", ref.SecretKey)
+		}
+		if ref.Type != "hex" && ref.EventID != "" {
+			fmt.Printf("event id:   %s\n", ref.EventID)
+		}
+		if ref.Type != "hex" && ref.PubKey != "" {
+			label := "pubkey:"
+			if ref.Type == "nevent" || ref.Type == "naddr" {
+				label = "author:"
+			}
+			fmt.Printf("%-11s %s\n", label, ref.PubKey)
+		}
+		if ref.Kind != nil {
+			fmt.Printf("kind:       %d\n", *ref.Kind)
+		}
+		if ref.Type == "naddr" {
+			fmt.Printf("identifier: %s\n", ref.Identifier)
+		}
+		for _, url := range ref.Relays {
+			fmt.Printf("relay:      %s\n", url)
+		}
+		return nil
+	},
+}
+
+func resolveEventID(value string) (*nostrkeys.Reference, error) {
+	ref, err := resolveEventRef(value)
+	if err != nil {
+		return nil, err
+	}
+	if ref.EventID == "" {
+		return nil, fmt.Errorf("%s has no event id", ref.Type)
+	}
+	return ref, nil
+}
+
+func printEncoded(value string, err error) error {
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{encodeNprofileCmd, encodeNeventCmd, encodeNaddrCmd} {
+		cmd.Flags().Var(&encodeRelays, "relay", "Relay hint to embed (repeatable)")
+	}
+	encodeNeventCmd.Flags().StringVar(&encodeAuthor, "author", "", "Author public key hint")
+	encodeNeventCmd.Flags().IntVar(&encodeEventKind, "kind", -1, "Event kind hint")
+	encodeNaddrCmd.Flags().StringVar(&encodeAuthor, "author", "", "Author public key (defaults to your configured key)")
+	encodeNaddrCmd.Flags().IntVar(&encodeAddrKind, "kind", 30023, "Event kind")
+	encodeNaddrCmd.Flags().StringVar(&encodeIdentifier, "identifier", "", "The d tag identifier")
+	registerProfileFlag(encodeNaddrCmd)
+	decodeCmd.Flags().BoolVar(&decodeJSON, "json", false, "Print the decoded fields as JSON")
+
+	encodeCmd.AddCommand(encodeNpubCmd)
+	encodeCmd.AddCommand(encodeNoteCmd)
+	encodeCmd.AddCommand(encodeNprofileCmd)
+	encodeCmd.AddCommand(encodeNeventCmd)
+	encodeCmd.AddCommand(encodeNaddrCmd)
+}
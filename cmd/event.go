@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/spf13/cobra"
+
+	"nostr-cli/nips/nip01"
+	nostrkeys "nostr-cli/nostr"
+)
+
+var eventCmd = &cobra.Command{
+	Use:   "event",
+	Short: "Sign, verify, or publish raw event JSON",
+	Long:  "Work with hand-crafted events of any kind: sign templates with your profile key, check IDs and signatures, and broadcast signed events.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var eventSignCmd = &cobra.Command{
+	Use:   "sign [file]",
+	Short: "Sign an unsigned event template",
+	Long:  "Read an event template (kind, content, tags) as JSON from a file or stdin, fill in pubkey and created_at, sign it with the active profile, and print the signed event.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		events, err := readEventsInput(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		sk, err := nostrkeys.PromptForDecryptedKey(profile)
+		if err != nil {
+			return err
+		}
+
+		for _, ev := range events {
+			if err := nip01.SignTemplate(profile, sk, ev); err != nil {
+				return err
+			}
+			output, err := json.Marshal(ev)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+		}
+		return nil
+	},
+}
+
+var eventVerifyCmd = &cobra.Command{
+	Use:   "verify [file]",
+	Short: "Check the ID and signature of event JSON",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		events, err := readEventsInput(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		invalid := 0
+		for _, ev := range events {
+			if err := nip01.VerifyEvent(ev); err != nil {
+				fmt.Printf("invalid %s: %v\n", ev.ID, err)
+				invalid++
+				continue
+			}
+			fmt.Printf("valid %s\n", ev.ID)
+		}
+		if invalid > 0 {
+			return fmt.Errorf("%d of %d event(s) failed verification", invalid, len(events))
+		}
+		return nil
+	},
+}
+
+var eventPublishCmd = &cobra.Command{
+	Use:   "publish [file]",
+	Short: "Broadcast already-signed events to your relays",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		events, err := readEventsInput(cmd, args)
+		if err != nil {
+			return err
+		}
+		for _, ev := range events {
+			if err := nip01.VerifyEvent(ev); err != nil {
+				return fmt.Errorf("refusing to publish %s: %w", ev.ID, err)
+			}
+		}
+
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, ev := range events {
+			if len(events) > 1 {
+				fmt.Printf("Event %s:\n", ev.ID)
+			}
+			results, err := nip01.PublishSigned(context.Background(), profile, ev, nil, publishPolicy(profile, ""))
+			printPublishResults(results)
+			printQueueNotice(ev, results)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", ev.ID, err))
+			}
+		}
+		return errors.Join(errs...)
+	},
+}
+
+func init() {
+	eventCmd.AddCommand(eventSignCmd)
+	eventCmd.AddCommand(eventVerifyCmd)
+	eventCmd.AddCommand(eventPublishCmd)
+	registerProfileFlag(eventSignCmd)
+	registerProfileFlag(eventPublishCmd)
+	registerPublishFlags(eventPublishCmd)
+}
+
+// readEventsInput decodes one or more JSON events (a single object, JSONL,
+// or concatenated objects) from the file argument or stdin.
+func readEventsInput(cmd *cobra.Command, args []string) ([]*nostrlib.Event, error) {
+	var input string
+	if len(args) > 0 && args[0] != "-" {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("reading event file: %w", err)
+		}
+		input = string(data)
+	} else {
+		data, ok, err := readInputFromStdin()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			_ = cmd.Help()
+			return nil, errors.New("provide event JSON as a file or on stdin")
+		}
+		input = data
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(input))
+	var events []*nostrlib.Event
+	for {
+		var ev nostrlib.Event
+		if err := decoder.Decode(&ev); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("parsing event JSON: %w", err)
+		}
+		events = append(events, &ev)
+	}
+	if len(events) == 0 {
+		return nil, errors.New("no event JSON found in input")
+	}
+	return events, nil
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/spf13/cobra"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip02"
+)
+
+var (
+	feedAuthors string
+	feedSince   string
+	feedUntil   string
+	feedLimit   int
+	feedJSON    bool
+)
+
+type feedItem struct {
+	ID        string             `json:"id"`
+	PubKey    string             `json:"pubkey"`
+	Author    string             `json:"author,omitempty"`
+	CreatedAt nostrlib.Timestamp `json:"created_at"`
+	Content   string             `json:"content"`
+	Tags      nostrlib.Tags      `json:"tags"`
+}
+
+var feedCmd = &cobra.Command{
+	Use:   "feed",
+	Short: "Read a timeline of notes from your relays",
+	Long:  "Fetch Kind 1 notes from the accounts you follow (or --authors) across your configured relays and their NIP-65 write relays, newest first.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		auth := relayAuth(profile, "")
+		ctx := context.Background()
+
+		authors, hints, err := resolvePubKeys(ctx, splitList(feedAuthors))
+		if err != nil {
+			return err
+		}
+		if len(authors) == 0 {
+			follows, err := nip02.FetchFollows(ctx, profile.Relays, profile.PublicKey, auth)
+			if err != nil {
+				return fmt.Errorf("loading your follow list (pass --authors to skip it): %w", err)
+			}
+			for _, follow := range follows {
+				authors = append(authors, follow.PubKey)
+			}
+		}
+		if len(authors) == 0 {
+			return errors.New("your follow list is empty; pass --authors instead")
+		}
+
+		filter := nostrlib.Filter{Kinds: []int{1}, Authors: authors, Limit: feedLimit}
+		if filter.Since, err = parseTimeFlag("since", feedSince); err != nil {
+			return err
+		}
+		if filter.Until, err = parseTimeFlag("until", feedUntil); err != nil {
+			return err
+		}
+
+		relays := outboxRelays(ctx, withRelayHints(profile.Relays, hints), authors, auth)
+		events, err := relay.QueryRelays(ctx, relays, filter, auth)
+		if err != nil {
+			return err
+		}
+		sort.Slice(events, func(i, j int) bool {
+			return events[i].CreatedAt > events[j].CreatedAt
+		})
+		if feedLimit > 0 && len(events) > feedLimit {
+			events = events[:feedLimit]
+		}
+
+		var pubKeys []string
+		for _, ev := range events {
+			pubKeys = append(pubKeys, ev.PubKey)
+		}
+		names := resolveDisplayNames(ctx, relays, pubKeys, auth)
+
+		if feedJSON {
+			items := make([]feedItem, 0, len(events))
+			for _, ev := range events {
+				items = append(items, feedItem{
+					ID:        ev.ID,
+					PubKey:    ev.PubKey,
+					Author:    names[ev.PubKey],
+					CreatedAt: ev.CreatedAt,
+					Content:   ev.Content,
+					Tags:      ev.Tags,
+				})
+			}
+			output, err := json.MarshalIndent(items, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+
+		if len(events) == 0 {
+			fmt.Println("No notes found.")
+			return nil
+		}
+		for _, ev := range events {
+			fmt.Printf("%s · %s · %s\n", authorLabel(names, ev.PubKey), formatTimestamp(ev.CreatedAt), shortID(ev.ID))
+			fmt.Println(strings.TrimSpace(ev.Content))
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+func init() {
+	feedCmd.Flags().StringVar(&feedAuthors, "authors", "", "Comma-separated public keys, npubs, nprofiles or nip05 addresses (defaults to your follow list)")
+	feedCmd.Flags().StringVar(&feedSince, "since", "", "Only notes after this time (unix, date, or duration like 24h)")
+	feedCmd.Flags().StringVar(&feedUntil, "until", "", "Only notes before this time (unix, date, or duration like 24h)")
+	feedCmd.Flags().IntVar(&feedLimit, "limit", 50, "Maximum number of notes to show")
+	feedCmd.Flags().BoolVar(&feedJSON, "json", false, "Print the timeline as JSON")
+	registerProfileFlag(feedCmd)
+}
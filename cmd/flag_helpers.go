@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// stringListFlag collects every occurrence of a repeatable flag.
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+func (f *stringListFlag) Type() string {
+	return "stringList"
+}
+
+// countFlag is an integer flag that rejects negative values.
+type countFlag int
+
+func (f *countFlag) String() string {
+	return strconv.Itoa(int(*f))
+}
+
+func (f *countFlag) Set(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errors.New("must be 0 or more")
+	}
+	*f = countFlag(n)
+	return nil
+}
+
+func (f *countFlag) Type() string {
+	return "int"
+}
@@ -0,0 +1,329 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/spf13/cobra"
+
+	"nostr-cli/nips/nip02"
+	nostrkeys "nostr-cli/nostr"
+)
+
+var (
+	followsRelay   string
+	followsPetname string
+	followsReplace bool
+	followsYes     bool
+)
+
+var followsCmd = &cobra.Command{
+	Use:   "follows",
+	Short: "List and edit your follow list (NIP-02)",
+	Long:  "Read the newest Kind 3 follow list from your relays and republish it with follows added or removed. Every existing tag and the content field are kept, and a list that would end up shorter than the newest one found is only published after you confirm.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var followsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the accounts you follow",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		auth := relayAuth(profile, "")
+		follows, err := nip02.FetchFollows(ctx, profile.Relays, profile.PublicKey, auth)
+		if err != nil {
+			return err
+		}
+		if len(follows) == 0 {
+			fmt.Println("Your follow list is empty.")
+			return nil
+		}
+
+		pubKeys := make([]string, 0, len(follows))
+		for _, follow := range follows {
+			pubKeys = append(pubKeys, follow.PubKey)
+		}
+		names := resolveDisplayNames(ctx, profile.Relays, pubKeys, auth)
+		fmt.Printf("Following %d account(s):\n", len(follows))
+		for _, follow := range follows {
+			npub, err := nostrkeys.HexToNpub(follow.PubKey)
+			if err != nil {
+				npub = follow.PubKey
+			}
+			line := npub
+			if name := names[follow.PubKey]; name != "" {
+				line += "  " + name
+			}
+			if follow.Petname != "" {
+				line += fmt.Sprintf("  (petname: %s)", follow.Petname)
+			}
+			if follow.Relay != "" {
+				line += "  " + follow.Relay
+			}
+			fmt.Println(line)
+		}
+		return nil
+	},
+}
+
+var followsAddCmd = &cobra.Command{
+	Use:   "add <pubkey> [pubkey...]",
+	Short: "Follow accounts by public key, npub, nprofile or nip05 address",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			_ = cmd.Help()
+			return errors.New("at least one public key is required")
+		}
+		if len(args) > 1 && (followsRelay != "" || followsPetname != "") {
+			return errors.New("--relay and --petname apply to a single account")
+		}
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+
+		var follows []nip02.Follow
+		for _, value := range args {
+			pubKey, hints, err := resolvePubKey(ctx, value)
+			if err != nil {
+				return err
+			}
+			follow := nip02.Follow{PubKey: pubKey, Relay: followsRelay, Petname: followsPetname}
+			if follow.Relay == "" && len(hints) > 0 {
+				follow.Relay = hints[0]
+			}
+			follows = append(follows, follow)
+		}
+		return editFollows(ctx, profile, func(tags nostrlib.Tags) nostrlib.Tags {
+			for _, follow := range follows {
+				var changed bool
+				if tags, changed = nip02.AddFollow(tags, follow); !changed {
+					fmt.Printf("Already following %s.\n", follow.PubKey)
+				}
+			}
+			return tags
+		})
+	},
+}
+
+var followsRemoveCmd = &cobra.Command{
+	Use:   "remove <pubkey> [pubkey...]",
+	Short: "Unfollow accounts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			_ = cmd.Help()
+			return errors.New("at least one public key is required")
+		}
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+
+		pubKeys, _, err := resolvePubKeys(ctx, args)
+		if err != nil {
+			return err
+		}
+		return editFollows(ctx, profile, func(tags nostrlib.Tags) nostrlib.Tags {
+			for _, pubKey := range pubKeys {
+				var removed bool
+				if tags, removed = nip02.RemoveFollow(tags, pubKey); !removed {
+					fmt.Printf("Not following %s.\n", pubKey)
+				}
+			}
+			return tags
+		})
+	},
+}
+
+var followsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print your follow list as JSON",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		follows, err := nip02.FetchFollows(context.Background(), profile.Relays, profile.PublicKey, relayAuth(profile, ""))
+		if err != nil {
+			return err
+		}
+		if follows == nil {
+			follows = []nip02.Follow{}
+		}
+		output, err := json.MarshalIndent(follows, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	},
+}
+
+var followsImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Add the follows from a JSON file or stdin",
+	Long:  "Read follows in the format printed by 'nostr follows export' (or a raw Kind 3 event) from a file or stdin and add them to your list. With --replace, accounts missing from the input are unfollowed.",
+	Args:  cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			_ = cmd.Help()
+			return errors.New("expected at most one file")
+		}
+		var data []byte
+		var err error
+		if len(args) == 1 && args[0] != "-" {
+			data, err = os.ReadFile(args[0])
+		} else {
+			var input string
+			var ok bool
+			input, ok, err = readInputFromStdin()
+			if err == nil && !ok {
+				return errors.New("pass a file or pipe the follow list on stdin")
+			}
+			data = []byte(input)
+		}
+		if err != nil {
+			return err
+		}
+		imported, err := parseFollowsImport(data)
+		if err != nil {
+			return err
+		}
+
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		return editFollows(context.Background(), profile, func(tags nostrlib.Tags) nostrlib.Tags {
+			if followsReplace {
+				keep := make(map[string]bool)
+				for _, follow := range imported {
+					keep[follow.PubKey] = true
+				}
+				for _, follow := range nip02.ParseFollows(tags) {
+					if !keep[follow.PubKey] {
+						tags, _ = nip02.RemoveFollow(tags, follow.PubKey)
+					}
+				}
+			}
+			for _, follow := range imported {
+				tags, _ = nip02.AddFollow(tags, follow)
+			}
+			return tags
+		})
+	},
+}
+
+func init() {
+	followsAddCmd.Flags().StringVar(&followsRelay, "relay", "", "Relay hint for the followed account")
+	followsAddCmd.Flags().StringVar(&followsPetname, "petname", "", "Local name for the followed account")
+	followsImportCmd.Flags().BoolVar(&followsReplace, "replace", false, "Unfollow accounts that are not in the input")
+	for _, cmd := range []*cobra.Command{followsAddCmd, followsRemoveCmd, followsImportCmd} {
+		cmd.Flags().BoolVar(&followsYes, "yes", false, "Publish without asking, even if the list gets shorter")
+		registerPublishFlags(cmd)
+		registerPublishJSONFlag(cmd)
+	}
+	followsCmd.AddCommand(followsListCmd)
+	followsCmd.AddCommand(followsAddCmd)
+	followsCmd.AddCommand(followsRemoveCmd)
+	followsCmd.AddCommand(followsImportCmd)
+	followsCmd.AddCommand(followsExportCmd)
+	registerProfileFlag(followsCmd)
+	registerProfileFlag(followsListCmd)
+	registerProfileFlag(followsAddCmd)
+	registerProfileFlag(followsRemoveCmd)
+	registerProfileFlag(followsImportCmd)
+	registerProfileFlag(followsExportCmd)
+}
+
+// editFollows applies edit to the newest follow list on the profile's relays
+// and republishes it, confirming first if follows would be lost.
+func editFollows(ctx context.Context, profile *nostrkeys.Profile, edit func(nostrlib.Tags) nostrlib.Tags) error {
+	current, err := nip02.FetchContactList(ctx, profile.Relays, profile.PublicKey, relayAuth(profile, ""))
+	if err != nil && !errors.Is(err, nip02.ErrNotFound) {
+		return fmt.Errorf("fetching your follow list: %w", err)
+	}
+	var tags nostrlib.Tags
+	if current != nil {
+		tags = current.Tags
+	}
+
+	updated := edit(tags)
+	if tagsEqual(tags, updated) {
+		fmt.Println("No changes were needed.")
+		return nil
+	}
+
+	before, after := len(nip02.ParseFollows(tags)), len(nip02.ParseFollows(updated))
+	if err := confirmListUpdate("follow list", "follow(s)", current != nil, before, after, followsYes); err != nil {
+		return err
+	}
+
+	sk, err := nostrkeys.PromptForDecryptedKey(profile)
+	if err != nil {
+		return err
+	}
+	ev, results, err := nip02.PublishContactList(ctx, profile, sk, current, updated, publishPolicy(profile, sk))
+	if ev == nil {
+		return err
+	}
+	if !publishJSON {
+		fmt.Printf("Publishing %d follow(s) (was %d).\n", after, before)
+	}
+	if reportErr := reportPublish(ev, results); reportErr != nil {
+		return reportErr
+	}
+	return err
+}
+
+func tagsEqual(a, b nostrlib.Tags) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if strings.Join(a[i], "\x00") != strings.Join(b[i], "\x00") {
+			return false
+		}
+	}
+	return true
+}
+
+// parseFollowsImport accepts the JSON array written by follows export or a
+// whole Kind 3 event.
+func parseFollowsImport(data []byte) ([]nip02.Follow, error) {
+	trimmed := strings.TrimSpace(string(data))
+	var raw []nip02.Follow
+	if strings.HasPrefix(trimmed, "{") {
+		var ev nostrlib.Event
+		if err := json.Unmarshal([]byte(trimmed), &ev); err != nil {
+			return nil, fmt.Errorf("parsing follow list: %w", err)
+		}
+		raw = nip02.ParseFollows(ev.Tags)
+	} else if err := json.Unmarshal([]byte(trimmed), &raw); err != nil {
+		return nil, fmt.Errorf("parsing follow list: %w", err)
+	}
+
+	follows := make([]nip02.Follow, 0, len(raw))
+	for _, follow := range raw {
+		pubKey, _, err := resolvePubKey(context.Background(), follow.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		follow.PubKey = pubKey
+		follows = append(follows, follow)
+	}
+	return follows, nil
+}
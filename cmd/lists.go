@@ -0,0 +1,359 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/spf13/cobra"
+
+	"nostr-cli/nips/nip05"
+	"nostr-cli/nips/nip51"
+	nostrkeys "nostr-cli/nostr"
+)
+
+var (
+	listsIdentifier string
+	listsTitle      string
+	listsPrivate    bool
+	listsYes        bool
+	listsJSON       bool
+)
+
+var listsCmd = &cobra.Command{
+	Use:   "lists",
+	Short: "Show and edit NIP-51 lists",
+	Long:  "Manage your mute list, pinned notes, bookmarks and follow sets. Items can be public tags or private entries encrypted to yourself, and every change republishes the whole list. Lists: " + listNames() + ".",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var listsShowCmd = &cobra.Command{
+	Use:   "show <list>",
+	Short: "Print the items in a list",
+	Long:  "Print a list's public and private items; private items need your password. For follow-set without --identifier, every follow set you published is summarized.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		spec, err := lookupListSpec(args[0])
+		if err != nil {
+			return err
+		}
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		auth := relayAuth(profile, "")
+
+		if spec.Kind == nip51.KindFollowSet && listsIdentifier == "" {
+			return printFollowSets(ctx, profile, spec)
+		}
+
+		ev, err := nip51.FetchList(ctx, profile.Relays, profile.PublicKey, spec.Kind, listsIdentifier, auth)
+		if err != nil {
+			return err
+		}
+		sk := ""
+		if strings.TrimSpace(ev.Content) != "" {
+			if sk, err = nostrkeys.PromptForDecryptedKey(profile); err != nil {
+				return err
+			}
+		}
+		list, err := nip51.Open(spec.Kind, listsIdentifier, ev, sk)
+		if err != nil {
+			return err
+		}
+
+		if listsJSON {
+			output, err := json.MarshalIndent(listJSON(list), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+		printList(ctx, profile, spec, list)
+		return nil
+	},
+}
+
+var listsAddCmd = &cobra.Command{
+	Use:   "add <list> <item> [item...]",
+	Short: "Add items to a list",
+	Long:  "Add people (public key, npub, nprofile or nip05), events (note, nevent, naddr), #hashtags or muted words to a list and republish it. --private encrypts the new items to yourself.",
+	Args:  cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			_ = cmd.Help()
+			return errors.New("expected a list name and at least one item")
+		}
+		return editList(args, func(list *nip51.List, item nostrlib.Tag) bool {
+			if !list.Add(item, listsPrivate) {
+				fmt.Printf("Already in the list: %s\n", item[1])
+				return false
+			}
+			return true
+		})
+	},
+}
+
+var listsRemoveCmd = &cobra.Command{
+	Use:   "remove <list> <item> [item...]",
+	Short: "Remove items from a list",
+	Args:  cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			_ = cmd.Help()
+			return errors.New("expected a list name and at least one item")
+		}
+		return editList(args, func(list *nip51.List, item nostrlib.Tag) bool {
+			if !list.Remove(item) {
+				fmt.Printf("Not in the list: %s\n", item[1])
+				return false
+			}
+			return true
+		})
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{listsShowCmd, listsAddCmd, listsRemoveCmd} {
+		cmd.Flags().StringVar(&listsIdentifier, "identifier", "", "The d identifier of a follow set")
+		registerProfileFlag(cmd)
+	}
+	listsShowCmd.Flags().BoolVar(&listsJSON, "json", false, "Print the list as JSON")
+	listsAddCmd.Flags().BoolVar(&listsPrivate, "private", false, "Encrypt the new items to yourself instead of listing them publicly")
+	listsAddCmd.Flags().StringVar(&listsTitle, "title", "", "Title for a follow set")
+	for _, cmd := range []*cobra.Command{listsAddCmd, listsRemoveCmd} {
+		cmd.Flags().BoolVar(&listsYes, "yes", false, "Publish without asking, even if the list gets shorter")
+		registerPublishFlags(cmd)
+		registerPublishJSONFlag(cmd)
+	}
+	listsCmd.AddCommand(listsShowCmd)
+	listsCmd.AddCommand(listsAddCmd)
+	listsCmd.AddCommand(listsRemoveCmd)
+	registerProfileFlag(listsCmd)
+}
+
+func listNames() string {
+	names := make([]string, 0, len(nip51.Specs))
+	for _, spec := range nip51.Specs {
+		names = append(names, spec.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func lookupListSpec(name string) (nip51.Spec, error) {
+	spec, ok := nip51.LookupSpec(strings.ToLower(strings.TrimSpace(name)))
+	if !ok {
+		return spec, fmt.Errorf("unknown list %q (use %s)", name, listNames())
+	}
+	return spec, nil
+}
+
+// editList applies change to every item in args[1:] on the newest version of
+// the list and republishes it, confirming first if items would be lost.
+func editList(args []string, change func(*nip51.List, nostrlib.Tag) bool) error {
+	spec, err := lookupListSpec(args[0])
+	if err != nil {
+		return err
+	}
+	if spec.Kind == nip51.KindFollowSet && listsIdentifier == "" {
+		return errors.New("follow sets need --identifier")
+	}
+	if listsTitle != "" && spec.Kind != nip51.KindFollowSet {
+		return errors.New("--title only applies to follow sets")
+	}
+	_, profile, _, err := loadProfileForCommand()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	var items []nostrlib.Tag
+	for _, value := range args[1:] {
+		item, err := parseListItem(ctx, spec, value)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+
+	current, err := nip51.FetchList(ctx, profile.Relays, profile.PublicKey, spec.Kind, listsIdentifier, relayAuth(profile, ""))
+	if err != nil && !errors.Is(err, nip51.ErrNotFound) {
+		return fmt.Errorf("fetching your %s: %w", strings.ToLower(spec.Title), err)
+	}
+	sk, err := nostrkeys.PromptForDecryptedKey(profile)
+	if err != nil {
+		return err
+	}
+	list, err := nip51.Open(spec.Kind, listsIdentifier, current, sk)
+	if err != nil {
+		return err
+	}
+
+	before := list.Len()
+	changed := false
+	for _, item := range items {
+		if change(list, item) {
+			changed = true
+		}
+	}
+	if listsTitle != "" && list.Title() != listsTitle {
+		list.SetTitle(listsTitle)
+		changed = true
+	}
+	if !changed {
+		fmt.Println("No changes were needed.")
+		return nil
+	}
+
+	name := strings.ToLower(spec.Title)
+	if err := confirmListUpdate(name, "item(s)", current != nil, before, list.Len(), listsYes); err != nil {
+		return err
+	}
+	ev, results, err := nip51.PublishList(ctx, profile, sk, list, publishPolicy(profile, sk))
+	if ev == nil {
+		return err
+	}
+	if !publishJSON {
+		fmt.Printf("Publishing %d item(s) (was %d).\n", list.Len(), before)
+	}
+	if reportErr := reportPublish(ev, results); reportErr != nil {
+		return reportErr
+	}
+	return err
+}
+
+// parseListItem turns a command-line value into the tag spec's list uses for
+// it: #hashtags become t tags, events e or a tags, people p tags, and for the
+// mute list anything else is a muted word.
+func parseListItem(ctx context.Context, spec nip51.Spec, value string) (nostrlib.Tag, error) {
+	value = strings.TrimSpace(value)
+	var item nostrlib.Tag
+	switch {
+	case strings.HasPrefix(value, "#") && len(value) > 1:
+		item = nostrlib.Tag{"t", strings.ToLower(value[1:])}
+	case nip05.IsIdentifier(value):
+		pubKey, _, err := resolvePubKey(ctx, value)
+		if err != nil {
+			return nil, err
+		}
+		item = nostrlib.Tag{"p", pubKey}
+	default:
+		ref, err := nostrkeys.DecodeReference(value)
+		switch {
+		case err != nil && spec.Accepts("word"):
+			item = nostrlib.Tag{"word", strings.ToLower(value)}
+		case err != nil:
+			return nil, fmt.Errorf("%q is not a public key, event reference or nip05 address", value)
+		case ref.Type == "naddr":
+			item = nostrlib.Tag{"a", fmt.Sprintf("%d:%s:%s", *ref.Kind, ref.PubKey, ref.Identifier)}
+		case ref.Type == "note" || ref.Type == "nevent" || (ref.Type == "hex" && !spec.Accepts("p")):
+			item = nostrlib.Tag{"e", ref.EventID}
+		case ref.Type == "hex" || ref.Type == "npub" || ref.Type == "nprofile":
+			item = nostrlib.Tag{"p", ref.PubKey}
+		default:
+			return nil, fmt.Errorf("%s references cannot be added to lists", ref.Type)
+		}
+		if ref != nil && len(ref.Relays) > 0 && (item[0] == "e" || item[0] == "a") {
+			item = append(item, ref.Relays[0])
+		}
+	}
+	if !spec.Accepts(item[0]) {
+		return nil, fmt.Errorf("%s cannot hold %q (it takes %s items)", strings.ToLower(spec.Title), value, strings.Join(spec.Items, ", "))
+	}
+	return item, nil
+}
+
+type listOutput struct {
+	Kind       int           `json:"kind"`
+	Identifier string        `json:"identifier,omitempty"`
+	Title      string        `json:"title,omitempty"`
+	Public     nostrlib.Tags `json:"public"`
+	Private    nostrlib.Tags `json:"private"`
+}
+
+func listJSON(list *nip51.List) listOutput {
+	output := listOutput{Kind: list.Kind, Identifier: list.Tags.GetD(), Title: list.Title(), Public: list.Items(), Private: list.Private}
+	if output.Public == nil {
+		output.Public = nostrlib.Tags{}
+	}
+	if output.Private == nil {
+		output.Private = nostrlib.Tags{}
+	}
+	return output
+}
+
+func printList(ctx context.Context, profile *nostrkeys.Profile, spec nip51.Spec, list *nip51.List) {
+	heading := spec.Title
+	if title := list.Title(); title != "" {
+		heading += ": " + title
+	}
+	fmt.Printf("%s (%d item(s), %d private)\n", heading, list.Len(), len(list.Private))
+
+	var pubKeys []string
+	for _, tags := range []nostrlib.Tags{list.Items(), list.Private} {
+		for _, tag := range tags {
+			if tag[0] == "p" {
+				pubKeys = append(pubKeys, tag[1])
+			}
+		}
+	}
+	names := resolveDisplayNames(ctx, profile.Relays, pubKeys, relayAuth(profile, ""))
+	for _, tag := range list.Items() {
+		fmt.Printf("  %-5s %s\n", tag[0], listItemLabel(names, tag))
+	}
+	for _, tag := range list.Private {
+		fmt.Printf("  %-5s %s  (private)\n", tag[0], listItemLabel(names, tag))
+	}
+}
+
+func listItemLabel(names map[string]string, tag nostrlib.Tag) string {
+	switch tag[0] {
+	case "p":
+		npub, err := nostrkeys.HexToNpub(tag[1])
+		if err != nil {
+			return tag[1]
+		}
+		if name := names[tag[1]]; name != "" {
+			return npub + "  " + name
+		}
+		return npub
+	case "e":
+		if note, err := nostrkeys.HexToNote(tag[1]); err == nil {
+			return note
+		}
+	case "t":
+		return "#" + tag[1]
+	}
+	return tag[1]
+}
+
+func printFollowSets(ctx context.Context, profile *nostrkeys.Profile, spec nip51.Spec) error {
+	sets, err := nip51.FetchSets(ctx, profile.Relays, profile.PublicKey, spec.Kind, relayAuth(profile, ""))
+	if err != nil {
+		return err
+	}
+	if len(sets) == 0 {
+		fmt.Println("You have no follow sets. Create one with 'nostr lists add follow-set --identifier <name> <pubkey>'.")
+		return nil
+	}
+	for _, ev := range sets {
+		// private items stay encrypted here, so no password is needed
+		list := &nip51.List{Kind: spec.Kind, Tags: ev.Tags}
+		line := fmt.Sprintf("%s  %d public", ev.Tags.GetD(), len(list.Items()))
+		if strings.TrimSpace(ev.Content) != "" {
+			line += " + private"
+		}
+		if title := list.Title(); title != "" {
+			line += "  " + title
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"nostr-cli/nips/nip05"
+)
+
+var nip05ExpectedPubKey string
+
+var nip05Cmd = &cobra.Command{
+	Use:   "nip05",
+	Short: "Verify or resolve NIP-05 addresses",
+	Long:  "Look up name@domain addresses through the domain's /.well-known/nostr.json file.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var nip05VerifyCmd = &cobra.Command{
+	Use:   "verify <name@domain>",
+	Short: "Check that an address maps to the expected public key",
+	Long:  "Fetch the domain's nostr.json, check that the name points to --pubkey (defaults to your configured key), and print the relays it advertises.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		expected := nip05ExpectedPubKey
+		if expected == "" {
+			_, profile, _, err := loadProfileForCommand()
+			if err != nil {
+				return err
+			}
+			expected = profile.PublicKey
+		} else {
+			var err error
+			if expected, _, err = resolvePubKey(ctx, expected); err != nil {
+				return err
+			}
+		}
+
+		result, err := nip05.Verify(ctx, args[0], expected)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s@%s verifies for %s\n", result.Name, result.Domain, result.PubKey)
+		printNIP05Relays(result.Relays)
+		return nil
+	},
+}
+
+var nip05ResolveCmd = &cobra.Command{
+	Use:   "resolve <name@domain>",
+	Short: "Print the public key behind an address",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		result, err := nip05.Lookup(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(result.PubKey)
+		return nil
+	},
+}
+
+func printNIP05Relays(relays []string) {
+	if len(relays) == 0 {
+		return
+	}
+	fmt.Println("Relays:")
+	for _, url := range relays {
+		fmt.Printf("  %s\n", url)
+	}
+}
+
+func init() {
+	nip05VerifyCmd.Flags().StringVar(&nip05ExpectedPubKey, "pubkey", "", "Expected public key (defaults to your configured key)")
+	registerProfileFlag(nip05VerifyCmd)
+	nip05Cmd.AddCommand(nip05VerifyCmd)
+	nip05Cmd.AddCommand(nip05ResolveCmd)
+}
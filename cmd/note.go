@@ -8,13 +8,22 @@ import (
 	"github.com/spf13/cobra"
 
 	"nostr-cli/nips/nip01"
+	"nostr-cli/nips/nip05"
+	"nostr-cli/nips/nip10"
+	"nostr-cli/nips/nip18"
 	nostrkeys "nostr-cli/nostr"
 )
 
+var (
+	noteReplyTo      string
+	noteQuote        string
+	noteResolveNIP05 bool
+)
+
 var noteCmd = &cobra.Command{
 	Use:   "note [message]",
 	Short: "Publish a short note (NIP-01)",
-	Long:  "Send a Kind 1 Nostr note to your configured relays using arguments or piped stdin.",
+	Long:  "Send a Kind 1 Nostr note to your configured relays using arguments or piped stdin. Use --reply-to to answer a note in its thread (NIP-10) or --quote to quote one (NIP-18). Mentions such as @npub1... and #hashtags are tagged automatically (NIP-27).",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		message := strings.TrimSpace(strings.Join(args, " "))
 		if message == "" {
@@ -26,7 +35,7 @@ var noteCmd = &cobra.Command{
 				message = strings.TrimSpace(input)
 			}
 		}
-		if message == "" {
+		if message == "" && noteQuote == "" {
 			_ = cmd.Help()
 			return fmt.Errorf("a note message is required")
 		}
@@ -36,16 +45,68 @@ var noteCmd = &cobra.Command{
 			return err
 		}
 
+		ctx := context.Background()
+		auth := relayAuth(profile, "")
+		opts := nip01.NoteOptions{Content: message}
+		if noteResolveNIP05 {
+			opts.Resolve = func(identifier string) (string, error) {
+				result, err := nip05.Lookup(ctx, identifier)
+				if err != nil {
+					return "", err
+				}
+				return result.PubKey, nil
+			}
+		}
+		if noteReplyTo != "" {
+			parent, hint, err := fetchReferencedEvent(ctx, profile.Relays, noteReplyTo, auth)
+			if err != nil {
+				return err
+			}
+			hint = authorRelayHint(ctx, profile.Relays, parent.PubKey, hint, auth)
+			opts.Tags = nip10.ReplyTags(parent, hint, profile.PublicKey)
+		}
+		if noteQuote != "" {
+			quoted, hint, err := fetchReferencedEvent(ctx, profile.Relays, noteQuote, auth)
+			if err != nil {
+				return err
+			}
+			hint = authorRelayHint(ctx, profile.Relays, quoted.PubKey, hint, auth)
+			for _, tag := range nip18.QuoteTags(quoted, hint) {
+				if tag[0] == "p" && tag[1] == profile.PublicKey {
+					continue
+				}
+				opts.Tags = nostrkeys.AppendUniqueTag(opts.Tags, tag)
+			}
+
+			var hints []string
+			if hint != "" {
+				hints = []string{hint}
+			}
+			nevent, err := nostrkeys.EncodeEvent(quoted.ID, hints, quoted.PubKey, quoted.Kind)
+			if err != nil {
+				return err
+			}
+			opts.Content = nip18.QuoteContent(opts.Content, "nostr:"+nevent)
+		}
+
 		sk, err := nostrkeys.PromptForDecryptedKey(profile)
 		if err != nil {
 			return err
 		}
 
-		ctx := context.Background()
-		return nip01.PublishNote(ctx, profile, sk, message)
+		ev, results, err := nip01.PublishNote(ctx, profile, sk, opts, publishPolicy(profile, sk))
+		if reportErr := reportPublish(ev, results); reportErr != nil {
+			return reportErr
+		}
+		return err
 	},
 }
 
 func init() {
+	noteCmd.Flags().StringVar(&noteReplyTo, "reply-to", "", "Reply to this note (hex id, note, nevent, or nostr: URI)")
+	noteCmd.Flags().StringVar(&noteQuote, "quote", "", "Quote this note (hex id, note, nevent, or nostr: URI)")
+	noteCmd.Flags().BoolVar(&noteResolveNIP05, "resolve-nip05", false, "Turn @name@domain handles in the text into mentions")
 	registerProfileFlag(noteCmd)
+	registerPublishFlags(noteCmd)
+	registerPublishJSONFlag(noteCmd)
 }
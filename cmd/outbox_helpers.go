@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip65"
+)
+
+// relaysPerUser caps how many of someone's relays are used, so reading from
+// or mentioning many people does not open a connection to every relay they
+// list.
+const relaysPerUser = 3
+
+// outboxRelays adds the NIP-65 write relays of pubKeys, where their own events
+// are published, to relays. Relay lists are discovered through relays and the
+// indexer relays and cached; authors without one are only looked for on relays.
+func outboxRelays(ctx context.Context, relays []string, pubKeys []string, auth *relay.Auth) []string {
+	lists := discoverRelayLists(ctx, relays, pubKeys, auth)
+	combined := append([]string{}, relays...)
+	for _, pubKey := range pubKeys {
+		if list, ok := lists[pubKey]; ok {
+			combined = withRelayHints(combined, firstRelays(list.WriteRelays()))
+		}
+	}
+	return combined
+}
+
+// inboxRelays returns the NIP-65 read relays of pubKeys, where replies,
+// mentions and reactions addressed to them belong.
+func inboxRelays(ctx context.Context, relays []string, pubKeys []string, auth *relay.Auth) []string {
+	lists := discoverRelayLists(ctx, relays, pubKeys, auth)
+	var inboxes []string
+	for _, pubKey := range pubKeys {
+		if list, ok := lists[pubKey]; ok {
+			inboxes = withRelayHints(inboxes, firstRelays(list.ReadRelays()))
+		}
+	}
+	return inboxes
+}
+
+// authorRelayHint picks the relay to cite an author's event with when the
+// reference carried no hint: one of the author's write relays, or none when
+// their relay list is unknown.
+func authorRelayHint(ctx context.Context, relays []string, pubKey, hint string, auth *relay.Auth) string {
+	if hint != "" {
+		return hint
+	}
+	if list := discoverRelayLists(ctx, relays, []string{pubKey}, auth)[pubKey]; list != nil {
+		if writes := list.WriteRelays(); len(writes) > 0 {
+			return writes[0]
+		}
+	}
+	return ""
+}
+
+// discoverRelayLists looks up relay lists for routing. A failed lookup is
+// reported but not fatal: the command carries on with what the cache had.
+func discoverRelayLists(ctx context.Context, relays []string, pubKeys []string, auth *relay.Auth) map[string]*nip65.RelayList {
+	lists, err := nip65.Discover(ctx, relays, pubKeys, auth)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Looking up relay lists failed, using the relays known so far: %v\n", err)
+	}
+	return lists
+}
+
+func firstRelays(relays []string) []string {
+	if len(relays) > relaysPerUser {
+		return relays[:relaysPerUser]
+	}
+	return relays
+}
@@ -5,27 +5,74 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
 	"nostr-cli/nips/nip00"
+	"nostr-cli/nips/nip05"
 	nostrkeys "nostr-cli/nostr"
 )
 
 var (
-	profileName      string
-	profileAbout     string
-	profilePicture   string
-	getProfilePubKey string
+	profileName        string
+	profileDisplayName string
+	profileAbout       string
+	profilePicture     string
+	profileBanner      string
+	profileWebsite     string
+	profileNIP05       string
+	profileLUD16       string
+	profileLUD06       string
+	profileBot         string
+	profileBirthday    string
+	profileUnset       stringListFlag
+	profileEdit        bool
+	profileEditFormat  string
+	getProfilePubKey   string
 )
 
+func profileFieldUpdates() [][2]string {
+	fields := [][2]string{
+		{"name", profileName},
+		{"display_name", profileDisplayName},
+		{"about", profileAbout},
+		{"picture", profilePicture},
+		{"banner", profileBanner},
+		{"website", profileWebsite},
+		{"nip05", profileNIP05},
+		{"lud16", profileLUD16},
+		{"lud06", profileLUD06},
+		{"bot", profileBot},
+		{"birthday", profileBirthday},
+	}
+	var updates [][2]string
+	for _, field := range fields {
+		if field[1] != "" {
+			updates = append(updates, field)
+		}
+	}
+	return updates
+}
+
 var profileCmd = &cobra.Command{
 	Use:   "set-profile",
 	Short: "Publish a Kind 0 profile event",
-	Long:  "Send a metadata (Kind 0) event with your preferred name, about blurb, and picture URL.",
+	Long:  "Update your metadata (Kind 0) event. Fields you do not pass, including ones set by other clients, are kept from the newest published version; use --unset to remove one.",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if profileName == "" && profileAbout == "" && profilePicture == "" {
-			return errors.New("provide at least one of --name, --about, or --picture")
+		updates := profileFieldUpdates()
+		if profileEdit {
+			if len(updates) > 0 || len(profileUnset) > 0 {
+				return errors.New("--edit cannot be combined with field flags or --unset")
+			}
+			_, activeProfile, _, err := loadProfileForCommand()
+			if err != nil {
+				return err
+			}
+			return editProfile(activeProfile, profileEditFormat)
+		}
+		if len(updates) == 0 && len(profileUnset) == 0 {
+			return errors.New("provide at least one field flag such as --name or --about, --unset <field>, or --edit")
 		}
 
 		_, activeProfile, _, err := loadProfileForCommand()
@@ -38,47 +85,58 @@ var profileCmd = &cobra.Command{
 			return err
 		}
 
-		metadata := nip00.ProfileMetadata{
-			Name:    profileName,
-			About:   profileAbout,
-			Picture: profilePicture,
+		metadata := nip00.ProfileMetadata{}
+		previous, err := nip00.FetchProfileEvent(context.Background(), activeProfile.Relays, activeProfile.PublicKey, relayAuth(activeProfile, sk))
+		switch {
+		case err == nil:
+			existing, err := nip00.ParseProfile(previous)
+			if err != nil {
+				return err
+			}
+			metadata = *existing
+		case !errors.Is(err, nip00.ErrProfileNotFound):
+			return fmt.Errorf("fetching current profile: %w", err)
 		}
 
-		if profileName == "" || profileAbout == "" || profilePicture == "" {
-			existing, err := nip00.FetchProfile(context.Background(), activeProfile.Relays, activeProfile.PublicKey)
-			if err == nil && existing != nil {
-				if metadata.Name == "" {
-					metadata.Name = existing.Name
-				}
-				if metadata.About == "" {
-					metadata.About = existing.About
-				}
-				if metadata.Picture == "" {
-					metadata.Picture = existing.Picture
-				}
+		for _, update := range updates {
+			if err := metadata.Set(update[0], update[1]); err != nil {
+				return err
+			}
+		}
+		for _, field := range profileUnset {
+			if err := metadata.Unset(field); err != nil {
+				return err
 			}
 		}
 
-		return nip00.PublishProfile(context.Background(), activeProfile, sk, metadata)
+		results, err := nip00.PublishProfile(context.Background(), activeProfile, sk, previous, metadata, publishPolicy(activeProfile, sk))
+		printPublishResults(results)
+		return err
 	},
 }
 
 var getProfileCmd = &cobra.Command{
 	Use:   "get-profile",
 	Short: "Show Kind 0 profile metadata",
-	Long:  "Fetch the latest metadata (Kind 0) event for your account or another public key from your configured relays.",
+	Long:  "Fetch the latest metadata (Kind 0) event for your account or another public key from your configured relays and the account's own NIP-65 write relays.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		_, activeProfile, _, err := loadProfileForCommand()
 		if err != nil {
 			return err
 		}
 
-		pubKey := getProfilePubKey
-		if pubKey == "" {
-			pubKey = activeProfile.PublicKey
+		ctx := context.Background()
+		pubKey := activeProfile.PublicKey
+		var hints []string
+		if getProfilePubKey != "" {
+			if pubKey, hints, err = resolvePubKey(ctx, getProfilePubKey); err != nil {
+				return err
+			}
 		}
 
-		profile, err := nip00.FetchProfile(context.Background(), activeProfile.Relays, pubKey)
+		auth := relayAuth(activeProfile, "")
+		relays := outboxRelays(ctx, withRelayHints(activeProfile.Relays, hints), []string{pubKey}, auth)
+		profile, err := nip00.FetchProfile(ctx, relays, pubKey, auth)
 		if err != nil {
 			return err
 		}
@@ -94,15 +152,34 @@ var getProfileCmd = &cobra.Command{
 		}
 
 		fmt.Println(string(output))
+		if profile.NIP05 != "" {
+			if _, err := nip05.Verify(ctx, profile.NIP05, pubKey); err != nil {
+				fmt.Fprintf(os.Stderr, "nip05 %s does not verify: %v\n", profile.NIP05, err)
+			} else {
+				fmt.Fprintf(os.Stderr, "nip05 %s verified\n", profile.NIP05)
+			}
+		}
 		return nil
 	},
 }
 
 func init() {
-	profileCmd.Flags().StringVar(&profileName, "name", "", "Display name for your profile")
+	profileCmd.Flags().StringVar(&profileName, "name", "", "Short name for your profile")
+	profileCmd.Flags().StringVar(&profileDisplayName, "display-name", "", "Longer display name")
 	profileCmd.Flags().StringVar(&profileAbout, "about", "", "Short bio or description")
 	profileCmd.Flags().StringVar(&profilePicture, "picture", "", "Profile picture URL")
-	getProfileCmd.Flags().StringVar(&getProfilePubKey, "pubkey", "", "Hex public key to inspect (defaults to your configured key)")
+	profileCmd.Flags().StringVar(&profileBanner, "banner", "", "Banner image URL")
+	profileCmd.Flags().StringVar(&profileWebsite, "website", "", "Website URL")
+	profileCmd.Flags().StringVar(&profileNIP05, "nip05", "", "NIP-05 identifier such as name@example.com")
+	profileCmd.Flags().StringVar(&profileLUD16, "lud16", "", "Lightning address")
+	profileCmd.Flags().StringVar(&profileLUD06, "lud06", "", "LNURL-pay string")
+	profileCmd.Flags().StringVar(&profileBot, "bot", "", "Mark the account as automated (true or false)")
+	profileCmd.Flags().StringVar(&profileBirthday, "birthday", "", "Birthday as YYYY-MM-DD or MM-DD")
+	profileCmd.Flags().Var(&profileUnset, "unset", "Remove a field such as lud06 (repeatable)")
+	profileCmd.Flags().BoolVar(&profileEdit, "edit", false, "Edit the current profile in $EDITOR, then review a diff before publishing")
+	profileCmd.Flags().StringVar(&profileEditFormat, "format", "json", "Format used by --edit: json or yaml")
+	getProfileCmd.Flags().StringVar(&getProfilePubKey, "pubkey", "", "Public key, npub, nprofile or nip05 address to inspect (defaults to your configured key)")
 	registerProfileFlag(profileCmd)
+	registerPublishFlags(profileCmd)
 	registerProfileFlag(getProfileCmd)
 }
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"nostr-cli/nips/nip00"
+	nostrkeys "nostr-cli/nostr"
+)
+
+// Text fields are always written to the editor, even when empty, so they can
+// be filled in without remembering their keys.
+var editableProfileFields = []string{"name", "display_name", "about", "picture", "banner", "website", "nip05", "lud16", "lud06"}
+
+func editProfile(activeProfile *nostrkeys.Profile, format string) error {
+	if format != "json" && format != "yaml" {
+		return fmt.Errorf("unsupported format %q (use json or yaml)", format)
+	}
+
+	current := nip00.ProfileMetadata{}
+	previous, err := nip00.FetchProfileEvent(context.Background(), activeProfile.Relays, activeProfile.PublicKey, relayAuth(activeProfile, ""))
+	switch {
+	case err == nil:
+		existing, err := nip00.ParseProfile(previous)
+		if err != nil {
+			return err
+		}
+		current = *existing
+	case !errors.Is(err, nip00.ErrProfileNotFound):
+		return fmt.Errorf("fetching current profile: %w", err)
+	}
+
+	original, err := renderProfile(current, format)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp("", "nostr-profile-*."+format)
+	if err != nil {
+		return err
+	}
+	path := file.Name()
+	file.Close()
+	defer os.Remove(path)
+
+	text := original
+	var edited nip00.ProfileMetadata
+	for {
+		if err := os.WriteFile(path, text, 0600); err != nil {
+			return err
+		}
+		if err := runEditor(path); err != nil {
+			return err
+		}
+		if text, err = os.ReadFile(path); err != nil {
+			return err
+		}
+
+		edited, err = parseProfile(text, format)
+		if err == nil {
+			err = edited.Validate()
+		}
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "Invalid profile:\n%v\n", err)
+		retry, promptErr := confirm("Edit again?")
+		if promptErr != nil || !retry {
+			return errors.New("profile not published")
+		}
+	}
+
+	updated, err := renderProfile(edited, format)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(original, updated) {
+		fmt.Println("No changes.")
+		return nil
+	}
+	for _, line := range lineDiff(string(original), string(updated)) {
+		fmt.Println(line)
+	}
+
+	publish, err := confirm("Publish this profile?")
+	if err != nil {
+		return fmt.Errorf("profile not published: %w", err)
+	}
+	if !publish {
+		return errors.New("profile not published")
+	}
+
+	sk, err := nostrkeys.PromptForDecryptedKey(activeProfile)
+	if err != nil {
+		return err
+	}
+	results, err := nip00.PublishProfile(context.Background(), activeProfile, sk, previous, edited, publishPolicy(activeProfile, sk))
+	printPublishResults(results)
+	return err
+}
+
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running editor %q: %w", editor, err)
+	}
+	return nil
+}
+
+// profileFields lists the profile's keys in a stable order: the editable text
+// fields first, then bot and birthday, then keys from other clients.
+func profileFields(profile nip00.ProfileMetadata) ([]string, map[string]json.RawMessage, error) {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, nil, err
+	}
+
+	keys := append([]string{}, editableProfileFields...)
+	ordered := map[string]bool{"bot": true, "birthday": true}
+	for _, key := range editableProfileFields {
+		ordered[key] = true
+		if _, ok := values[key]; !ok {
+			values[key] = json.RawMessage(`""`)
+		}
+	}
+	var rest []string
+	for key := range values {
+		if !ordered[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	for _, key := range []string{"bot", "birthday"} {
+		if _, ok := values[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return append(keys, rest...), values, nil
+}
+
+func renderProfile(profile nip00.ProfileMetadata, format string) ([]byte, error) {
+	keys, values, err := profileFields(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "json" {
+		var buf bytes.Buffer
+		buf.WriteString("{\n")
+		for i, key := range keys {
+			var value bytes.Buffer
+			if err := json.Indent(&value, values[key], "  ", "  "); err != nil {
+				return nil, err
+			}
+			name, _ := json.Marshal(key)
+			fmt.Fprintf(&buf, "  %s: %s", name, value.Bytes())
+			if i < len(keys)-1 {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n")
+		return buf.Bytes(), nil
+	}
+
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range keys {
+		// JSON is valid YAML, so each value decodes straight into a node.
+		var value yaml.Node
+		if err := yaml.Unmarshal(values[key], &value); err != nil {
+			return nil, err
+		}
+		node := value.Content[0]
+		blockStyle(node)
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, node)
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// blockStyle drops the flow style inherited from JSON and keeps multi-line
+// strings such as about readable as literal blocks.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		if strings.Contains(node.Value, "\n") {
+			node.Style = yaml.LiteralStyle
+		} else if node.Value == "" {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+func parseProfile(text []byte, format string) (nip00.ProfileMetadata, error) {
+	data := text
+	if format == "yaml" {
+		var values map[string]any
+		if err := yaml.Unmarshal(text, &values); err != nil {
+			return nip00.ProfileMetadata{}, err
+		}
+		var err error
+		if data, err = json.Marshal(values); err != nil {
+			return nip00.ProfileMetadata{}, err
+		}
+	}
+
+	var profile nip00.ProfileMetadata
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nip00.ProfileMetadata{}, err
+	}
+	return profile, nil
+}
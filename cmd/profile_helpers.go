@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"sync"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
 	"github.com/spf13/cobra"
 
+	"nostr-cli/internal/relay"
 	nostrkeys "nostr-cli/nostr"
 )
 
@@ -21,3 +25,28 @@ func loadProfileForCommand() (*nostrkeys.Config, *nostrkeys.Profile, string, err
 func registerProfileFlag(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&profileOverride, "profile", "", "Use the named profile for this command")
 }
+
+// relayAuth signs NIP-42 challenges for the profile's opted-in relays. When
+// sk is empty the password is only requested once a relay actually asks.
+func relayAuth(profile *nostrkeys.Profile, sk string) *relay.Auth {
+	if len(profile.AuthRelays) == 0 {
+		return nil
+	}
+	var once sync.Once
+	var keyErr error
+	return &relay.Auth{
+		Relays: profile.AuthRelays,
+		Sign: func(ev *nostrlib.Event) error {
+			once.Do(func() {
+				if sk == "" {
+					sk, keyErr = nostrkeys.PromptForDecryptedKey(profile)
+				}
+			})
+			if keyErr != nil {
+				return keyErr
+			}
+			ev.PubKey = profile.PublicKey
+			return ev.Sign(sk)
+		},
+	}
+}
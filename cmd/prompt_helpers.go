@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// confirm asks a yes/no question. When stdin carries piped input the answer
+// is read from the controlling terminal instead.
+func confirm(prompt string) (bool, error) {
+	input := os.Stdin
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		tty, err := os.Open("/dev/tty")
+		if err != nil {
+			return false, fmt.Errorf("no terminal available to answer %q; use --yes to skip the question", prompt)
+		}
+		defer tty.Close()
+		input = tty
+	}
+
+	fmt.Printf("%s [y/N]: ", prompt)
+	answer, err := bufio.NewReader(input).ReadString('\n')
+	if err != nil && strings.TrimSpace(answer) == "" {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// confirmListUpdate asks before publishing a replaceable list that would end
+// up shorter than the newest version found, or that starts over because no
+// version was found: a relay that missed recent updates must not silently
+// cost the user their entries.
+func confirmListUpdate(name, unit string, found bool, before, after int, yes bool) error {
+	prompt := ""
+	switch {
+	case !found:
+		prompt = fmt.Sprintf("No %s was found on your relays. Publish a new one with %d %s?", name, after, unit)
+	case after < before:
+		prompt = fmt.Sprintf("This shrinks your %s from %d to %d %s. Publish anyway?", name, before, after, unit)
+	}
+	if prompt == "" || yes {
+		return nil
+	}
+	ok, err := confirm(prompt)
+	if err != nil {
+		return fmt.Errorf("%s not published: %w", name, err)
+	}
+	if !ok {
+		return fmt.Errorf("%s not published", name)
+	}
+	return nil
+}
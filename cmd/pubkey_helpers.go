@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip05"
+	nostrkeys "nostr-cli/nostr"
+)
+
+// resolvePubKey accepts hex, npub, nprofile, nostr: URIs and NIP-05
+// addresses. Relay hints from nprofile or nostr.json are returned with it.
+func resolvePubKey(ctx context.Context, value string) (string, []string, error) {
+	if nip05.IsIdentifier(value) {
+		result, err := nip05.Lookup(ctx, value)
+		if err != nil {
+			return "", nil, fmt.Errorf("resolving %s: %w", value, err)
+		}
+		return result.PubKey, result.Relays, nil
+	}
+	ref, err := nostrkeys.DecodeReference(value)
+	if err != nil {
+		return "", nil, fmt.Errorf("%q is not a public key, npub, nprofile or nip05 address", value)
+	}
+	switch ref.Type {
+	case "hex", "npub", "nprofile":
+		return ref.PubKey, ref.Relays, nil
+	}
+	return "", nil, fmt.Errorf("expected a public key, got %s", ref.Type)
+}
+
+func resolvePubKeys(ctx context.Context, values []string) ([]string, []string, error) {
+	resolved := make([]string, 0, len(values))
+	var hints []string
+	for _, value := range values {
+		pubKey, relays, err := resolvePubKey(ctx, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		resolved = append(resolved, pubKey)
+		hints = append(hints, relays...)
+	}
+	return resolved, hints, nil
+}
+
+// resolveEventRef accepts hex event ids, note, nevent and naddr, with or
+// without the nostr: prefix.
+func resolveEventRef(value string) (*nostrkeys.Reference, error) {
+	ref, err := nostrkeys.DecodeReference(value)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not an event id, note, nevent or naddr", value)
+	}
+	switch ref.Type {
+	case "hex", "note", "nevent", "naddr":
+		return ref, nil
+	}
+	return nil, fmt.Errorf("expected an event reference, got %s", ref.Type)
+}
+
+// resolveEventRefs splits references into event ids and naddr addresses.
+func resolveEventRefs(values []string) ([]string, []*nostrkeys.Reference, []string, error) {
+	var ids, hints []string
+	var addresses []*nostrkeys.Reference
+	for _, value := range values {
+		ref, err := resolveEventRef(value)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if ref.Type == "naddr" {
+			addresses = append(addresses, ref)
+		} else {
+			ids = append(ids, ref.EventID)
+		}
+		hints = append(hints, ref.Relays...)
+	}
+	return ids, addresses, hints, nil
+}
+
+func withRelayHints(relays, hints []string) []string {
+	combined := append([]string{}, relays...)
+	for _, hint := range hints {
+		if !relayInList(combined, hint) {
+			combined = append(combined, hint)
+		}
+	}
+	return combined
+}
+
+// fetchReferencedEvent loads the event behind a hex id, note, nevent or naddr
+// from relays plus the reference's own hints and, when it names the author,
+// the author's write relays. The first hint, if any, is returned for citing
+// the event in tags.
+func fetchReferencedEvent(ctx context.Context, relays []string, value string, auth *relay.Auth) (*nostrlib.Event, string, error) {
+	ref, err := resolveEventRef(value)
+	if err != nil {
+		return nil, "", err
+	}
+	relays = withRelayHints(relays, ref.Relays)
+	if ref.PubKey != "" {
+		relays = outboxRelays(ctx, relays, []string{ref.PubKey}, auth)
+	}
+
+	var ev *nostrlib.Event
+	if ref.Type == "naddr" {
+		filter := nostrlib.Filter{Kinds: []int{*ref.Kind}, Authors: []string{ref.PubKey}, Tags: nostrlib.TagMap{"d": []string{ref.Identifier}}}
+		ev, err = relay.FetchLatest(ctx, relays, filter, auth)
+	} else {
+		ev, err = relay.FetchEvent(ctx, relays, ref.EventID, auth)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching %s: %w", value, err)
+	}
+
+	hint := ""
+	if len(ref.Relays) > 0 {
+		hint = ref.Relays[0]
+	}
+	return ev, hint, nil
+}
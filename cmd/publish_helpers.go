@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/spf13/cobra"
+
+	"nostr-cli/internal/queue"
+	"nostr-cli/internal/relay"
+	nostrkeys "nostr-cli/nostr"
+)
+
+var (
+	publishMinAcks countFlag = 1
+	publishRetries int
+	publishJSON    bool
+)
+
+type publishSummary struct {
+	ID     string                `json:"id"`
+	Kind   int                   `json:"kind"`
+	Nevent string                `json:"nevent,omitempty"`
+	Naddr  string                `json:"naddr,omitempty"`
+	URI    string                `json:"uri,omitempty"`
+	Relays []publishRelaySummary `json:"relays"`
+	Queued []string              `json:"queued,omitempty"`
+}
+
+type publishRelaySummary struct {
+	URL       string `json:"url"`
+	Accepted  bool   `json:"accepted"`
+	Message   string `json:"message,omitempty"`
+	Error     string `json:"error,omitempty"`
+	LatencyMS int64  `json:"latency_ms"`
+	Attempts  int    `json:"attempts"`
+}
+
+func registerPublishFlags(cmd *cobra.Command) {
+	cmd.Flags().Var(&publishMinAcks, "min-acks", "Minimum number of relays that must accept the event (0 for best effort)")
+	cmd.Flags().IntVar(&publishRetries, "retries", relay.DefaultRetries, "Retries per relay for transient failures")
+}
+
+func publishPolicy(profile *nostrkeys.Profile, sk string) relay.Policy {
+	policy := relay.DefaultPolicy()
+	policy.MinAcks = int(publishMinAcks)
+	policy.Retries = publishRetries
+	policy.Auth = relayAuth(profile, sk)
+	policy.Inboxes = func(ctx context.Context, pubKeys []string) []string {
+		return inboxRelays(ctx, profile.Relays, pubKeys, policy.Auth)
+	}
+	return policy
+}
+
+func printPublishResults(results []relay.PublishResult) {
+	for _, result := range results {
+		if result.Accepted {
+			fmt.Printf("Published to %s (%dms)\n", result.Relay, result.Latency.Milliseconds())
+			continue
+		}
+		fmt.Printf("Failed to publish to %s after %d attempt(s): %v\n", result.Relay, result.Attempts, result.Err)
+	}
+}
+
+func registerPublishJSONFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&publishJSON, "json", false, "Print a JSON summary with the event id, links, and relay results")
+}
+
+// reportPublish prints the relay results followed by the event id and its
+// shareable forms, or everything as one JSON object with --json.
+func reportPublish(ev *nostrlib.Event, results []relay.PublishResult) error {
+	summary := summarizePublish(ev, results)
+	if publishJSON {
+		output, err := json.MarshalIndent(summary, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+
+	printPublishResults(results)
+	printQueueNotice(ev, results)
+	if ev == nil {
+		return nil
+	}
+	fmt.Printf("Event ID: %s\n", summary.ID)
+	if summary.Nevent != "" {
+		fmt.Printf("nevent:   %s\n", summary.Nevent)
+	}
+	if summary.Naddr != "" {
+		fmt.Printf("naddr:    %s\n", summary.Naddr)
+	}
+	if summary.URI != "" {
+		fmt.Printf("URI:      %s\n", summary.URI)
+	}
+	return nil
+}
+
+func summarizePublish(ev *nostrlib.Event, results []relay.PublishResult) publishSummary {
+	summary := publishSummary{Relays: []publishRelaySummary{}, Queued: queuedRelays(ev, results)}
+	var accepted []string
+	for _, result := range results {
+		entry := publishRelaySummary{
+			URL:       result.Relay,
+			Accepted:  result.Accepted,
+			Message:   result.Message,
+			LatencyMS: result.Latency.Milliseconds(),
+			Attempts:  result.Attempts,
+		}
+		if result.Err != nil {
+			entry.Error = result.Err.Error()
+		}
+		if result.Accepted {
+			accepted = append(accepted, result.Relay)
+		}
+		summary.Relays = append(summary.Relays, entry)
+	}
+	if ev == nil {
+		return summary
+	}
+
+	summary.ID = ev.ID
+	summary.Kind = ev.Kind
+	if relay.IsAddressable(ev.Kind) {
+		summary.Naddr, _ = nostrkeys.EncodeAddress(ev.PubKey, ev.Kind, ev.Tags.GetD(), accepted)
+		if summary.Naddr != "" {
+			summary.URI = "nostr:" + summary.Naddr
+		}
+	} else {
+		summary.Nevent, _ = nostrkeys.EncodeEvent(ev.ID, accepted, ev.PubKey, ev.Kind)
+		if summary.Nevent != "" {
+			summary.URI = "nostr:" + summary.Nevent
+		}
+	}
+	return summary
+}
+
+// queuedRelays lists the relays ev was kept in the outbox queue for.
+func queuedRelays(ev *nostrlib.Event, results []relay.PublishResult) []string {
+	if ev == nil || !queue.Queueable(ev.Kind) {
+		return nil
+	}
+	return queue.PendingRelays(results)
+}
+
+func printQueueNotice(ev *nostrlib.Event, results []relay.PublishResult) {
+	pending := queuedRelays(ev, results)
+	if len(pending) == 0 {
+		return
+	}
+	fmt.Printf("Saved to the outbox queue for %d relay(s); run 'nostr queue flush' to resend.\n", len(pending))
+	printAuthNotice(results)
+}
+
+// printAuthNotice points out relays that turned the event away until they
+// are authenticated to.
+func printAuthNotice(results []relay.PublishResult) {
+	for _, result := range results {
+		if result.AuthRequired() {
+			fmt.Printf("%s requires NIP-42 authentication (opt in with 'nostr relays auth %s'); the event stays queued.\n", result.Relay, result.Relay)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	"nostr-cli/internal/relay"
+	nostrkeys "nostr-cli/nostr"
+)
+
+func signedTestEvent(t *testing.T, kind int, tags nostrlib.Tags) *nostrlib.Event {
+	t.Helper()
+	ev := &nostrlib.Event{Kind: kind, Content: "summary", CreatedAt: nostrlib.Timestamp(1700000000), Tags: tags}
+	if err := ev.Sign(nostrlib.GeneratePrivateKey()); err != nil {
+		t.Fatal(err)
+	}
+	return ev
+}
+
+var summaryResults = []relay.PublishResult{
+	{Relay: "wss://accepted.example", Accepted: true, Latency: 42 * time.Millisecond, Attempts: 1},
+	{Relay: "wss://duplicate.example", Accepted: true, Message: "duplicate: have it", Attempts: 1},
+	{Relay: "wss://down.example", Retryable: true, Attempts: 3, Err: errors.New("connecting: refused")},
+	{Relay: "wss://blocked.example", Message: "blocked: no", Attempts: 1, Err: errors.New("rejected: blocked: no")},
+}
+
+func TestSummarizePublishNote(t *testing.T) {
+	ev := signedTestEvent(t, 1, nostrlib.Tags{})
+	summary := summarizePublish(ev, summaryResults)
+
+	if summary.ID != ev.ID || summary.Kind != 1 || summary.Naddr != "" || summary.URI != "nostr:"+summary.Nevent {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	ref, err := nostrkeys.DecodeReference(summary.URI)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", summary.URI, err)
+	}
+	if ref.Type != "nevent" || ref.EventID != ev.ID || ref.PubKey != ev.PubKey || ref.Kind == nil || *ref.Kind != 1 {
+		t.Fatalf("unexpected nevent %+v", ref)
+	}
+	if !reflect.DeepEqual(ref.Relays, []string{"wss://accepted.example", "wss://duplicate.example"}) {
+		t.Fatalf("expected only accepting relays as hints, got %v", ref.Relays)
+	}
+	if !reflect.DeepEqual(summary.Queued, []string{"wss://down.example"}) {
+		t.Fatalf("unexpected queued relays %v", summary.Queued)
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"id", "kind", "nevent", "uri", "relays", "queued"} {
+		if _, ok := decoded[key]; !ok {
+			t.Fatalf("expected %q in %s", key, data)
+		}
+	}
+	if _, ok := decoded["naddr"]; ok {
+		t.Fatalf("expected no naddr for a note in %s", data)
+	}
+	relays := decoded["relays"].([]any)
+	if len(relays) != 4 {
+		t.Fatalf("expected four relay results in %s", data)
+	}
+	accepted := relays[0].(map[string]any)
+	if accepted["url"] != "wss://accepted.example" || accepted["accepted"] != true || accepted["latency_ms"] != float64(42) || accepted["attempts"] != float64(1) {
+		t.Fatalf("unexpected relay result %v", accepted)
+	}
+	down := relays[2].(map[string]any)
+	if down["accepted"] != false || down["error"] != "connecting: refused" || down["attempts"] != float64(3) {
+		t.Fatalf("unexpected relay result %v", down)
+	}
+}
+
+func TestSummarizePublishArticle(t *testing.T) {
+	ev := signedTestEvent(t, 30023, nostrlib.Tags{{"d", "my-article"}})
+	summary := summarizePublish(ev, summaryResults[:1])
+
+	if summary.Nevent != "" || summary.URI != "nostr:"+summary.Naddr || len(summary.Queued) != 0 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	ref, err := nostrkeys.DecodeReference(summary.Naddr)
+	if err != nil {
+		t.Fatalf("decoding %s: %v", summary.Naddr, err)
+	}
+	if ref.Type != "naddr" || ref.PubKey != ev.PubKey || ref.Identifier != "my-article" || *ref.Kind != 30023 || !reflect.DeepEqual(ref.Relays, []string{"wss://accepted.example"}) {
+		t.Fatalf("unexpected naddr %+v", ref)
+	}
+}
+
+func TestSummarizePublishSkipsQueueForReplaceableKinds(t *testing.T) {
+	summary := summarizePublish(signedTestEvent(t, 3, nostrlib.Tags{}), summaryResults)
+	if len(summary.Queued) != 0 {
+		t.Fatalf("expected a follow list not to be queued, got %v", summary.Queued)
+	}
+	if summary := summarizePublish(nil, summaryResults); summary.ID != "" || summary.URI != "" || len(summary.Relays) != 4 {
+		t.Fatalf("unexpected summary without an event %+v", summary)
+	}
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"nostr-cli/internal/queue"
+	"nostr-cli/internal/relay"
+)
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Inspect or resend events waiting in the outbox queue",
+	Long:  "Notes and articles that could not reach every relay are kept in a local queue and can be resent later without decrypting your key again.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmd.Help()
+	},
+}
+
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show queued events",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := queue.Load()
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println("The outbox queue is empty.")
+			return nil
+		}
+		for _, entry := range entries {
+			fmt.Printf("%s  kind %d  queued %s  attempts %d\n", entry.ID, entry.Event.Kind, entry.QueuedAt.Local().Format("2006-01-02 15:04"), entry.Attempts)
+			fmt.Printf("  pending: %s\n", strings.Join(entry.Relays, ", "))
+			if entry.LastError != "" {
+				fmt.Printf("  last error: %s\n", entry.LastError)
+			}
+		}
+		return nil
+	},
+}
+
+var queueFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Resend queued events to the relays that still need them",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		policy := relay.DefaultPolicy()
+		policy.Retries = publishRetries
+		// the key is only asked for once a relay in AuthRelays demands it
+		if _, profile, _, err := loadProfileForCommand(); err == nil {
+			policy.Auth = relayAuth(profile, "")
+		}
+		flushed, err := queue.Flush(context.Background(), policy)
+		if len(flushed) == 0 && err == nil {
+			fmt.Println("The outbox queue is empty.")
+			return nil
+		}
+		remaining := 0
+		for _, result := range flushed {
+			fmt.Printf("Event %s:\n", result.Entry.ID)
+			printPublishResults(result.Results)
+			printAuthNotice(result.Results)
+			if !result.Done {
+				remaining++
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if remaining > 0 {
+			return fmt.Errorf("%d event(s) are still waiting for at least one relay", remaining)
+		}
+		fmt.Println("All queued events were delivered.")
+		return nil
+	},
+}
+
+var queueDropCmd = &cobra.Command{
+	Use:   "drop <id>",
+	Short: "Remove an event from the queue without sending it",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dropped, err := queue.Drop(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Dropped queued event %s\n", dropped.ID)
+		return nil
+	},
+}
+
+func init() {
+	queueFlushCmd.Flags().IntVar(&publishRetries, "retries", relay.DefaultRetries, "Retries per relay for transient failures")
+	registerProfileFlag(queueFlushCmd)
+	queueCmd.AddCommand(queueListCmd)
+	queueCmd.AddCommand(queueFlushCmd)
+	queueCmd.AddCommand(queueDropCmd)
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/spf13/cobra"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip25"
+	nostrkeys "nostr-cli/nostr"
+)
+
+var (
+	reactEmojiURL string
+	reactionsJSON bool
+)
+
+var reactCmd = &cobra.Command{
+	Use:   "react <event> [+|-|emoji]",
+	Short: "React to an event (NIP-25)",
+	Long:  "Publish a Kind 7 reaction to a note or other event. The reaction defaults to \"+\" (like); \"-\" is a dislike, and any emoji works. For a custom emoji (NIP-30) pass :shortcode: together with --emoji-url.",
+	Args:  cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 || len(args) > 2 {
+			_ = cmd.Help()
+			return errors.New("expected an event reference and an optional reaction")
+		}
+		content := "+"
+		if len(args) == 2 {
+			content = args[1]
+		}
+		if _, ok := nip25.Shortcode(content); ok && reactEmojiURL == "" {
+			return fmt.Errorf("custom emoji %s needs --emoji-url", content)
+		}
+
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		auth := relayAuth(profile, "")
+
+		target, hint, err := fetchReferencedEvent(ctx, profile.Relays, args[0], auth)
+		if err != nil {
+			return err
+		}
+		hint = authorRelayHint(ctx, profile.Relays, target.PubKey, hint, auth)
+
+		sk, err := nostrkeys.PromptForDecryptedKey(profile)
+		if err != nil {
+			return err
+		}
+		ev, results, err := nip25.PublishReaction(ctx, profile, sk, target, content, reactEmojiURL, hint, nil, publishPolicy(profile, sk))
+		if ev == nil {
+			return err
+		}
+		if reportErr := reportPublish(ev, results); reportErr != nil {
+			return reportErr
+		}
+		return err
+	},
+}
+
+var reactionsCmd = &cobra.Command{
+	Use:   "reactions <event>",
+	Short: "Count the reactions to an event",
+	Long:  "Fetch Kind 7 reactions to an event from your relays, any relay hints, and the author's NIP-65 read relays, and show how many of each there are and who sent them.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		auth := relayAuth(profile, "")
+
+		ref, err := resolveEventRef(args[0])
+		if err != nil {
+			return err
+		}
+		relays := withRelayHints(profile.Relays, ref.Relays)
+		if ref.PubKey != "" {
+			// reactions are delivered to the author's inbox
+			relays = withRelayHints(relays, inboxRelays(ctx, relays, []string{ref.PubKey}, auth))
+		}
+		// reactions to an addressable event carry its address, whichever
+		// version they were made to
+		target, tags := ref.EventID, nostrlib.TagMap{"e": []string{ref.EventID}}
+		if ref.Type == "naddr" {
+			target = nip25.Address(*ref.Kind, ref.PubKey, ref.Identifier)
+			tags = nostrlib.TagMap{"a": []string{target}}
+		}
+
+		events, err := relay.QueryRelays(ctx, relays, nostrlib.Filter{Kinds: []int{nip25.KindReaction}, Tags: tags}, auth)
+		if err != nil {
+			return err
+		}
+		var verified []*nostrlib.Event
+		for _, ev := range events {
+			if ok, _ := ev.CheckSignature(); ok {
+				verified = append(verified, ev)
+			}
+		}
+		summaries := nip25.Aggregate(target, verified)
+
+		if reactionsJSON {
+			output, err := json.MarshalIndent(summaries, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+		if len(summaries) == 0 {
+			fmt.Println("No reactions found.")
+			return nil
+		}
+
+		var pubKeys []string
+		for _, summary := range summaries {
+			pubKeys = append(pubKeys, summary.Authors...)
+		}
+		names := resolveDisplayNames(ctx, relays, pubKeys, auth)
+		for _, summary := range summaries {
+			fmt.Printf("%s  %d\n", summary.Content, summary.Count)
+			for _, pubKey := range summary.Authors {
+				fmt.Printf("  %s\n", authorLabel(names, pubKey))
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	reactCmd.Flags().StringVar(&reactEmojiURL, "emoji-url", "", "Image URL for a :shortcode: custom emoji (NIP-30)")
+	registerProfileFlag(reactCmd)
+	registerPublishFlags(reactCmd)
+	registerPublishJSONFlag(reactCmd)
+	reactionsCmd.Flags().BoolVar(&reactionsJSON, "json", false, "Print the reaction counts as JSON")
+	registerProfileFlag(reactionsCmd)
+}
@@ -5,11 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"time"
 
-	nostrlib "github.com/nbd-wtf/go-nostr"
 	"github.com/spf13/cobra"
 
+	"nostr-cli/nips/nip65"
 	nostrkeys "nostr-cli/nostr"
 )
 
@@ -32,35 +31,77 @@ var relaysListCmd = &cobra.Command{
 			return nil
 		}
 		for i, relay := range profile.Relays {
-			fmt.Printf("%d. %s\n", i+1, strings.TrimSpace(relay))
+			marker := ""
+			if direction := profile.RelayMarker(relay); direction != "" {
+				marker = " (" + direction + ")"
+			}
+			if relayInList(profile.AuthRelays, relay) {
+				marker += " (auth)"
+			}
+			fmt.Printf("%d. %s%s\n", i+1, strings.TrimSpace(relay), marker)
 		}
 		return nil
 	},
 }
 
+var (
+	relaysAddRead  bool
+	relaysAddWrite bool
+)
+
 var relaysAddCmd = &cobra.Command{
 	Use:   "add <relay> [relay...]",
 	Short: "Add relay URLs to the config",
+	Long:  "Add relays to the config. By default a relay is used both to read and to publish; --read or --write limits it to one direction (the NIP-65 markers), also for relays that are already configured.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
 			_ = cmd.Help()
 			return fmt.Errorf("at least one relay URL is required")
 		}
+		if relaysAddRead && relaysAddWrite {
+			return errors.New("use --read or --write, not both; relays are used both ways by default")
+		}
+		marker := ""
+		switch {
+		case relaysAddRead:
+			marker = nostrkeys.MarkerRead
+		case relaysAddWrite:
+			marker = nostrkeys.MarkerWrite
+		}
 		cfg, profile, alias, err := loadProfileForCommand()
 		if err != nil {
 			return err
 		}
 		added := addRelaysToProfile(profile, args)
-		if len(added) == 0 {
+		var remarked []string
+		for _, arg := range args {
+			relay := cleanRelayURL(arg)
+			if relay == "" || profile.RelayMarker(relay) == marker {
+				continue
+			}
+			profile.SetRelayMarker(relay, marker)
+			if !relayInList(added, relay) {
+				remarked = append(remarked, relay)
+			}
+		}
+		if len(added) == 0 && len(remarked) == 0 {
 			fmt.Println("All provided relays are already configured.")
 			return nil
 		}
 		if err := nostrkeys.SaveConfig(cfg); err != nil {
 			return err
 		}
-		fmt.Printf("Added %d relay(s) to '%s':\n", len(added), alias)
-		for _, relay := range added {
-			fmt.Printf("- %s\n", relay)
+		if len(added) > 0 {
+			fmt.Printf("Added %d relay(s) to '%s':\n", len(added), alias)
+			for _, relay := range added {
+				fmt.Printf("- %s\n", relayWithMarker(profile, relay))
+			}
+		}
+		if len(remarked) > 0 {
+			fmt.Printf("Updated %d relay(s) in '%s':\n", len(remarked), alias)
+			for _, relay := range remarked {
+				fmt.Printf("- %s\n", relayWithMarker(profile, relay))
+			}
 		}
 		return nil
 	},
@@ -82,6 +123,9 @@ var relaysRemoveCmd = &cobra.Command{
 		if len(removed) == 0 {
 			return fmt.Errorf("none of the provided relays were configured")
 		}
+		for _, relay := range removed {
+			profile.SetRelayMarker(relay, "")
+		}
 		if err := nostrkeys.SaveConfig(cfg); err != nil {
 			return err
 		}
@@ -99,7 +143,7 @@ var relaysRemoveCmd = &cobra.Command{
 var relaysPullCmd = &cobra.Command{
 	Use:   "pull",
 	Short: "Pull relay metadata via the outbox model",
-	Long:  "Connect to configured relays, fetch the latest kind 10002 event, and update the local relay list.",
+	Long:  "Connect to configured relays, fetch the latest kind 10002 event, and replace the local relay list and its read/write markers with it. Run 'nostr relays diff' first to see what changes.",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, profile, alias, err := loadProfileForCommand()
 		if err != nil {
@@ -116,21 +160,71 @@ var relaysPullCmd = &cobra.Command{
 		}
 
 		ctx := context.Background()
-		fetched, err := fetchRelaysFromOutbox(ctx, queryRelays, pubKey)
+		list, err := nip65.FetchRelayList(ctx, queryRelays, pubKey, relayAuth(profile, ""))
 		if err != nil {
 			return err
 		}
 
-		if len(fetched) == 0 {
+		if len(list.WriteRelays()) == 0 {
 			return errors.New("no writable relays were advertised by your outbox")
 		}
 
-		profile.Relays = fetched
+		list.ApplyTo(profile)
+		if err := nostrkeys.SaveConfig(cfg); err != nil {
+			return err
+		}
+		fmt.Printf("Synchronized %d relay(s) into '%s' from outbox metadata:\n", len(profile.Relays), alias)
+		for _, relay := range profile.Relays {
+			fmt.Printf("- %s\n", relayWithMarker(profile, relay))
+		}
+		return nil
+	},
+}
+
+var relaysAuthDisable bool
+
+var relaysAuthCmd = &cobra.Command{
+	Use:   "auth <relay> [relay...]",
+	Short: "Allow NIP-42 authentication to relays",
+	Long:  "Opt the given relays into NIP-42 AUTH so publishing and queries answer their challenges with your key. Use --disable to opt out again.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			_ = cmd.Help()
+			return fmt.Errorf("at least one relay URL is required")
+		}
+		cfg, profile, alias, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		var changed []string
+		for _, arg := range args {
+			trimmed := cleanRelayURL(arg)
+			if trimmed == "" {
+				continue
+			}
+			enabled := relayInList(profile.AuthRelays, trimmed)
+			switch {
+			case relaysAuthDisable && enabled:
+				profile.AuthRelays = removeRelayFromList(profile.AuthRelays, trimmed)
+				changed = append(changed, trimmed)
+			case !relaysAuthDisable && !enabled:
+				profile.AuthRelays = append(profile.AuthRelays, trimmed)
+				changed = append(changed, trimmed)
+			}
+		}
+		if len(changed) == 0 {
+			fmt.Println("No changes were needed.")
+			return nil
+		}
 		if err := nostrkeys.SaveConfig(cfg); err != nil {
 			return err
 		}
-		fmt.Printf("Synchronized %d relay(s) into '%s' from outbox metadata:\n", len(fetched), alias)
-		for _, relay := range fetched {
+		action := "Enabled"
+		if relaysAuthDisable {
+			action = "Disabled"
+		}
+		fmt.Printf("%s NIP-42 authentication for %d relay(s) in '%s':\n", action, len(changed), alias)
+		for _, relay := range changed {
 			fmt.Printf("- %s\n", relay)
 		}
 		return nil
@@ -138,15 +232,20 @@ var relaysPullCmd = &cobra.Command{
 }
 
 func init() {
+	relaysAddCmd.Flags().BoolVar(&relaysAddRead, "read", false, "Only read from these relays (your inbox)")
+	relaysAddCmd.Flags().BoolVar(&relaysAddWrite, "write", false, "Only publish to these relays (your outbox)")
+	relaysAuthCmd.Flags().BoolVar(&relaysAuthDisable, "disable", false, "Stop authenticating to the given relays")
 	relaysCmd.AddCommand(relaysListCmd)
 	relaysCmd.AddCommand(relaysAddCmd)
 	relaysCmd.AddCommand(relaysRemoveCmd)
 	relaysCmd.AddCommand(relaysPullCmd)
+	relaysCmd.AddCommand(relaysAuthCmd)
 	registerProfileFlag(relaysCmd)
 	registerProfileFlag(relaysListCmd)
 	registerProfileFlag(relaysAddCmd)
 	registerProfileFlag(relaysRemoveCmd)
 	registerProfileFlag(relaysPullCmd)
+	registerProfileFlag(relaysAuthCmd)
 }
 
 func addRelaysToProfile(profile *nostrkeys.Profile, relays []string) []string {
@@ -211,67 +310,32 @@ func removeRelaysFromProfile(profile *nostrkeys.Profile, targets []string) (remo
 	return removed, missing
 }
 
-func fetchRelaysFromOutbox(ctx context.Context, candidateRelays []string, pubKey string) ([]string, error) {
-	seen := make(map[string]struct{})
-	var discovered []string
-
-	for _, url := range candidateRelays {
-		trimmed := cleanRelayURL(url)
-		if trimmed == "" {
-			continue
-		}
-
-		connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-		relay, err := nostrlib.RelayConnect(connectCtx, trimmed)
-		cancel()
-		if err != nil {
-			fmt.Printf("Failed to connect to %s: %v\n", trimmed, err)
-			continue
-		}
-
-		queryCtx, queryCancel := context.WithTimeout(ctx, 5*time.Second)
-		events, err := relay.QuerySync(queryCtx, nostrlib.Filter{Authors: []string{pubKey}, Kinds: []int{10002}, Limit: 1})
-		queryCancel()
-		relay.Close()
-		if err != nil {
-			fmt.Printf("Failed to query %s: %v\n", trimmed, err)
-			continue
-		}
-		if len(events) == 0 {
-			continue
+func relayInList(list []string, target string) bool {
+	key := normalizedRelayKey(target)
+	for _, relay := range list {
+		if normalizedRelayKey(relay) == key {
+			return true
 		}
+	}
+	return false
+}
 
-		for _, ev := range events {
-			for _, tag := range ev.Tags {
-				if len(tag) < 2 || tag[0] != "r" {
-					continue
-				}
-				permission := ""
-				if len(tag) >= 3 {
-					permission = strings.ToLower(strings.TrimSpace(tag[2]))
-				}
-				if permission == "read" {
-					continue
-				}
-				relayURL := cleanRelayURL(tag[1])
-				if relayURL == "" {
-					continue
-				}
-				key := normalizedRelayKey(relayURL)
-				if _, ok := seen[key]; ok {
-					continue
-				}
-				seen[key] = struct{}{}
-				discovered = append(discovered, relayURL)
-			}
+func removeRelayFromList(list []string, target string) []string {
+	key := normalizedRelayKey(target)
+	var remaining []string
+	for _, relay := range list {
+		if normalizedRelayKey(relay) != key {
+			remaining = append(remaining, relay)
 		}
 	}
+	return remaining
+}
 
-	if len(discovered) == 0 {
-		return nil, errors.New("no relay list metadata was found on the queried relays")
+func relayWithMarker(profile *nostrkeys.Profile, relay string) string {
+	if marker := profile.RelayMarker(relay); marker != "" {
+		return relay + " (" + marker + ")"
 	}
-
-	return discovered, nil
+	return relay
 }
 
 func cleanRelayURL(url string) string {
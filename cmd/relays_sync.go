@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/spf13/cobra"
+
+	"nostr-cli/nips/nip65"
+	nostrkeys "nostr-cli/nostr"
+)
+
+var (
+	relaysNoIndexers bool
+	relaysPushYes    bool
+)
+
+var relaysPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Publish the configured relays as your NIP-65 relay list",
+	Long:  "Sign a kind 10002 relay list from the configured relays and their read/write markers, and send it to those relays plus well-known indexer relays so others can find you. If the newest published list has relays the config lacks, the difference is shown and you are asked before they are dropped.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		local := nip65.FromProfile(profile)
+		if len(local.WriteRelays()) == 0 {
+			return errors.New("the config has no relays to publish to; add one with 'nostr relays add <url>'")
+		}
+		ctx := context.Background()
+
+		published, err := fetchPublishedRelayList(ctx, profile)
+		if err != nil {
+			return err
+		}
+		var previous *nostrlib.Event
+		if published != nil {
+			previous = published.Event
+			dropped := droppedRelays(published, local)
+			switch {
+			case len(dropped) > 0 && !relaysPushYes:
+				printRelayListDiff(published, local)
+				ok, err := confirm(fmt.Sprintf("Publishing drops %d relay(s) from your published list. Continue?", len(dropped)))
+				if err != nil {
+					return fmt.Errorf("relay list not published: %w", err)
+				}
+				if !ok {
+					return errors.New("relay list not published")
+				}
+			case equalLines(relayListLines(published), relayListLines(local)):
+				fmt.Println("Your published relay list already matches the config.")
+				return nil
+			}
+		}
+
+		sk, err := nostrkeys.PromptForDecryptedKey(profile)
+		if err != nil {
+			return err
+		}
+		ev, results, err := nip65.PublishRelayList(ctx, profile, sk, local, previous, relayIndexers(), publishPolicy(profile, sk))
+		if ev == nil {
+			return err
+		}
+		if reportErr := reportPublish(ev, results); reportErr != nil {
+			return reportErr
+		}
+		return err
+	},
+}
+
+var relaysDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare the configured relays with your published relay list",
+	Long:  "Fetch the newest kind 10002 relay list from your relays and the indexer relays and show how it differs from the config: lines starting with - are only published, lines with + only configured. Use 'relays push' or 'relays pull' to bring one side in line with the other.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		published, err := fetchPublishedRelayList(context.Background(), profile)
+		if err != nil {
+			return err
+		}
+		local := nip65.FromProfile(profile)
+		if published == nil {
+			fmt.Println("No published relay list was found. 'nostr relays push' would publish:")
+			for _, line := range relayListLines(local) {
+				fmt.Printf("+ %s\n", line)
+			}
+			return nil
+		}
+		if equalLines(relayListLines(published), relayListLines(local)) {
+			fmt.Printf("The relay list published %s matches the config.\n", formatTimestamp(published.Event.CreatedAt))
+			return nil
+		}
+		printRelayListDiff(published, local)
+		return nil
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{relaysPushCmd, relaysDiffCmd} {
+		cmd.Flags().BoolVar(&relaysNoIndexers, "no-indexers", false, "Only use the configured relays, not the well-known indexer relays")
+		registerProfileFlag(cmd)
+	}
+	relaysPushCmd.Flags().BoolVar(&relaysPushYes, "yes", false, "Publish without asking, even if relays are dropped from the published list")
+	registerPublishFlags(relaysPushCmd)
+	registerPublishJSONFlag(relaysPushCmd)
+	relaysCmd.AddCommand(relaysPushCmd)
+	relaysCmd.AddCommand(relaysDiffCmd)
+}
+
+func relayIndexers() []string {
+	if relaysNoIndexers {
+		return nil
+	}
+	return nip65.IndexerRelays
+}
+
+// fetchPublishedRelayList returns the newest relay list on the configured and
+// indexer relays, or nil when there is none.
+func fetchPublishedRelayList(ctx context.Context, profile *nostrkeys.Profile) (*nip65.RelayList, error) {
+	relays := withRelayHints(profile.Relays, relayIndexers())
+	list, err := nip65.FetchRelayList(ctx, relays, profile.PublicKey, relayAuth(profile, ""))
+	if errors.Is(err, nip65.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching your published relay list: %w", err)
+	}
+	return list, nil
+}
+
+// relayListLines renders one sorted line per relay, with its marker, so two
+// lists can be diffed.
+func relayListLines(list *nip65.RelayList) []string {
+	lines := make([]string, 0, len(list.Entries))
+	for _, entry := range list.Entries {
+		line := strings.ToLower(entry.URL)
+		if marker := entry.Marker(); marker != "" {
+			line += " (" + marker + ")"
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+// droppedRelays lists the published relays that the config does not have at
+// all; a changed marker does not count.
+func droppedRelays(published, local *nip65.RelayList) []string {
+	configured := make(map[string]bool)
+	for _, entry := range local.Entries {
+		configured[normalizedRelayKey(entry.URL)] = true
+	}
+	var dropped []string
+	for _, entry := range published.Entries {
+		if !configured[normalizedRelayKey(entry.URL)] {
+			dropped = append(dropped, entry.URL)
+		}
+	}
+	return dropped
+}
+
+func printRelayListDiff(published, local *nip65.RelayList) {
+	fmt.Printf("--- published %s\n+++ config\n", formatTimestamp(published.Event.CreatedAt))
+	for _, line := range lineDiff(strings.Join(relayListLines(published), "\n"), strings.Join(relayListLines(local), "\n")) {
+		fmt.Println(line)
+	}
+}
+
+func equalLines(a, b []string) bool {
+	return strings.Join(a, "\n") == strings.Join(b, "\n")
+}
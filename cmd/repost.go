@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"nostr-cli/nips/nip18"
+	nostrkeys "nostr-cli/nostr"
+)
+
+var repostCmd = &cobra.Command{
+	Use:   "repost <event>",
+	Short: "Repost an event (NIP-18)",
+	Long:  "Fetch an event and publish a repost that embeds it: Kind 6 for notes, or a Kind 16 generic repost for anything else such as articles. The repost also goes to the author's read relays.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		auth := relayAuth(profile, "")
+
+		target, hint, err := fetchReferencedEvent(ctx, profile.Relays, args[0], auth)
+		if err != nil {
+			return err
+		}
+		hint = authorRelayHint(ctx, profile.Relays, target.PubKey, hint, auth)
+
+		sk, err := nostrkeys.PromptForDecryptedKey(profile)
+		if err != nil {
+			return err
+		}
+		ev, results, err := nip18.PublishRepost(ctx, profile, sk, target, hint, nil, publishPolicy(profile, sk))
+		if ev == nil {
+			return err
+		}
+		if reportErr := reportPublish(ev, results); reportErr != nil {
+			return reportErr
+		}
+		return err
+	},
+}
+
+func init() {
+	registerProfileFlag(repostCmd)
+	registerPublishFlags(repostCmd)
+	registerPublishJSONFlag(repostCmd)
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/spf13/cobra"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip01"
+)
+
+var (
+	reqIDs     string
+	reqAuthors string
+	reqKinds   string
+	reqE       string
+	reqP       string
+	reqT       string
+	reqTags    stringListFlag
+	reqSince   string
+	reqUntil   string
+	reqLimit   int
+	reqRelays  stringListFlag
+)
+
+var reqCmd = &cobra.Command{
+	Use:   "req [filter-json]",
+	Short: "Query relays with a raw NIP-01 filter",
+	Long:  "Send a REQ built from filter JSON (argument or stdin) and/or flags to your relays or --relay overrides, and print the verified, deduplicated events as JSON lines.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter, hints, err := buildReqFilter(args)
+		if err != nil {
+			return err
+		}
+
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		relays := []string(reqRelays)
+		if len(relays) == 0 {
+			relays = profile.Relays
+		}
+		relays = withRelayHints(relays, hints)
+
+		events, err := relay.QueryRelays(context.Background(), relays, filter, relayAuth(profile, ""))
+		if err != nil {
+			return err
+		}
+		// events with bad signatures are dropped before the limit applies, so
+		// they cannot crowd out valid ones
+		var verified []*nostrlib.Event
+		for _, ev := range events {
+			if err := nip01.VerifyEvent(ev); err != nil {
+				fmt.Fprintf(os.Stderr, "skipping %s: %v\n", ev.ID, err)
+				continue
+			}
+			verified = append(verified, ev)
+		}
+		sort.SliceStable(verified, func(i, j int) bool {
+			return verified[i].CreatedAt > verified[j].CreatedAt
+		})
+		if filter.Limit > 0 && len(verified) > filter.Limit {
+			verified = verified[:filter.Limit]
+		}
+
+		for _, ev := range verified {
+			line, err := json.Marshal(ev)
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(line))
+		}
+		return nil
+	},
+}
+
+func init() {
+	reqCmd.Flags().StringVar(&reqIDs, "ids", "", "Comma-separated event ids, notes, nevents or naddrs")
+	reqCmd.Flags().StringVar(&reqAuthors, "authors", "", "Comma-separated author public keys, npubs, nprofiles or nip05 addresses")
+	reqCmd.Flags().StringVar(&reqKinds, "kinds", "", "Comma-separated event kinds")
+	reqCmd.Flags().StringVar(&reqE, "e", "", "Comma-separated event ids, notes or nevents for the #e tag filter (naddrs become #a)")
+	reqCmd.Flags().StringVar(&reqP, "p", "", "Comma-separated public keys, npubs, nprofiles or nip05 addresses for the #p tag filter")
+	reqCmd.Flags().StringVar(&reqT, "t", "", "Comma-separated values for the #t tag filter")
+	reqCmd.Flags().Var(&reqTags, "tag", "Other tag filters such as d=my-article (repeatable)")
+	reqCmd.Flags().StringVar(&reqSince, "since", "", "Only events after this time (unix, date, or duration like 24h)")
+	reqCmd.Flags().StringVar(&reqUntil, "until", "", "Only events before this time (unix, date, or duration like 24h)")
+	reqCmd.Flags().IntVar(&reqLimit, "limit", 0, "Maximum number of events")
+	reqCmd.Flags().Var(&reqRelays, "relay", "Query this relay instead of the configured ones (repeatable)")
+	registerProfileFlag(reqCmd)
+}
+
+func buildReqFilter(args []string) (nostrlib.Filter, []string, error) {
+	var filter nostrlib.Filter
+
+	raw := strings.TrimSpace(strings.Join(args, " "))
+	if raw == "" {
+		input, ok, err := readInputFromStdin()
+		if err != nil {
+			return filter, nil, err
+		}
+		if ok {
+			raw = strings.TrimSpace(input)
+		}
+	}
+	if raw != "" {
+		if err := json.Unmarshal([]byte(raw), &filter); err != nil {
+			return filter, nil, fmt.Errorf("parsing filter JSON: %w", err)
+		}
+	}
+
+	ids, addresses, hints, err := resolveEventRefs(splitList(reqIDs))
+	if err != nil {
+		return filter, nil, err
+	}
+	if len(ids) > 0 {
+		filter.IDs = ids
+	}
+	authors, authorHints, err := resolvePubKeys(context.Background(), splitList(reqAuthors))
+	if err != nil {
+		return filter, nil, err
+	}
+	hints = append(hints, authorHints...)
+	if len(authors) > 0 {
+		filter.Authors = authors
+	}
+	if reqKinds != "" {
+		kinds, err := parseKinds(reqKinds)
+		if err != nil {
+			return filter, nil, err
+		}
+		filter.Kinds = kinds
+	}
+
+	tags, err := parseTagFilters(reqTags)
+	if err != nil {
+		return filter, nil, err
+	}
+	pTags, pHints, err := resolvePubKeys(context.Background(), splitList(reqP))
+	if err != nil {
+		return filter, nil, err
+	}
+	eTags, eAddresses, eHints, err := resolveEventRefs(splitList(reqE))
+	if err != nil {
+		return filter, nil, err
+	}
+	hints = append(append(hints, pHints...), eHints...)
+	var aTags []string
+	for _, ref := range eAddresses {
+		aTags = append(aTags, fmt.Sprintf("%d:%s:%s", *ref.Kind, ref.PubKey, ref.Identifier))
+	}
+	for name, values := range map[string][]string{"e": eTags, "a": aTags, "p": pTags, "t": splitList(reqT)} {
+		if len(values) > 0 {
+			if tags == nil {
+				tags = make(nostrlib.TagMap)
+			}
+			tags[name] = append(tags[name], values...)
+		}
+	}
+	for name, values := range tags {
+		if filter.Tags == nil {
+			filter.Tags = make(nostrlib.TagMap)
+		}
+		filter.Tags[name] = values
+	}
+
+	if since, err := parseTimeFlag("since", reqSince); err != nil {
+		return filter, nil, err
+	} else if since != nil {
+		filter.Since = since
+	}
+	if until, err := parseTimeFlag("until", reqUntil); err != nil {
+		return filter, nil, err
+	} else if until != nil {
+		filter.Until = until
+	}
+	if reqLimit > 0 {
+		filter.Limit = reqLimit
+	}
+	// an naddr has no event id, so it selects the replaceable event by kind,
+	// author and d tag; combined with ids that no event matches both of
+	if len(addresses) > 0 && len(filter.IDs) > 0 {
+		return filter, nil, errors.New("naddrs cannot be queried together with event ids; run a separate req for each")
+	}
+	for _, ref := range addresses {
+		filter.Kinds = append(filter.Kinds, *ref.Kind)
+		filter.Authors = append(filter.Authors, ref.PubKey)
+		if filter.Tags == nil {
+			filter.Tags = make(nostrlib.TagMap)
+		}
+		filter.Tags["d"] = append(filter.Tags["d"], ref.Identifier)
+	}
+
+	if len(filter.IDs) == 0 && len(filter.Authors) == 0 && len(filter.Kinds) == 0 && len(filter.Tags) == 0 && filter.Since == nil && filter.Until == nil && filter.Limit == 0 {
+		return filter, nil, fmt.Errorf("the filter is empty; pass filter JSON or flags such as --kinds or --authors")
+	}
+	return filter, hints, nil
+}
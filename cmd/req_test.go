@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	nostrkeys "nostr-cli/nostr"
+)
+
+// setReqFlags resets every req flag for the test and restores them after.
+func setReqFlags(t *testing.T, set func()) {
+	t.Helper()
+	reset := func() {
+		reqIDs, reqAuthors, reqKinds, reqE, reqP, reqT = "", "", "", "", "", ""
+		reqTags, reqRelays = nil, nil
+		reqSince, reqUntil = "", ""
+		reqLimit = 0
+	}
+	reset()
+	t.Cleanup(reset)
+	set()
+}
+
+func testPubKey(t *testing.T) string {
+	t.Helper()
+	pk, err := nostrlib.GetPublicKey(nostrlib.GeneratePrivateKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pk
+}
+
+func TestBuildReqFilterMergesFlagsIntoJSON(t *testing.T) {
+	author := testPubKey(t)
+	nprofile, err := nostrkeys.EncodeProfile(author, []string{"wss://author.example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mentioned := testPubKey(t)
+	setReqFlags(t, func() {
+		reqAuthors = nprofile
+		reqP = mentioned
+		reqT = "nostr,dev"
+		reqTags = stringListFlag{"d=my-article"}
+		reqLimit = 5
+	})
+
+	filter, hints, err := buildReqFilter([]string{`{"kinds":[1,30023],"limit":20}`})
+	if err != nil {
+		t.Fatalf("build filter: %v", err)
+	}
+	if !reflect.DeepEqual(filter.Kinds, []int{1, 30023}) || !reflect.DeepEqual(filter.Authors, []string{author}) || filter.Limit != 5 {
+		t.Fatalf("unexpected filter %+v", filter)
+	}
+	expected := nostrlib.TagMap{"p": {mentioned}, "t": {"nostr", "dev"}, "d": {"my-article"}}
+	if !reflect.DeepEqual(filter.Tags, expected) {
+		t.Fatalf("unexpected tags %v", filter.Tags)
+	}
+	if !reflect.DeepEqual(hints, []string{"wss://author.example"}) {
+		t.Fatalf("unexpected hints %v", hints)
+	}
+}
+
+func TestBuildReqFilterResolvesNaddr(t *testing.T) {
+	author := testPubKey(t)
+	naddr, err := nostrkeys.EncodeAddress(author, 30023, "my-article", []string{"wss://articles.example"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	setReqFlags(t, func() { reqIDs = naddr })
+
+	filter, hints, err := buildReqFilter([]string{"{}"})
+	if err != nil {
+		t.Fatalf("build filter: %v", err)
+	}
+	if len(filter.IDs) != 0 || !reflect.DeepEqual(filter.Kinds, []int{30023}) || !reflect.DeepEqual(filter.Authors, []string{author}) {
+		t.Fatalf("unexpected filter %+v", filter)
+	}
+	if !reflect.DeepEqual(filter.Tags, nostrlib.TagMap{"d": {"my-article"}}) {
+		t.Fatalf("unexpected tags %v", filter.Tags)
+	}
+	if !reflect.DeepEqual(hints, []string{"wss://articles.example"}) {
+		t.Fatalf("unexpected hints %v", hints)
+	}
+}
+
+func TestBuildReqFilterRejectsNaddrWithIDs(t *testing.T) {
+	naddr, err := nostrkeys.EncodeAddress(testPubKey(t), 30023, "my-article", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := strings.Repeat("ab", 32)
+	setReqFlags(t, func() { reqIDs = naddr + "," + id })
+	if _, _, err := buildReqFilter([]string{"{}"}); err == nil || !strings.Contains(err.Error(), "naddr") {
+		t.Fatalf("expected naddrs mixed with ids to be rejected, got %v", err)
+	}
+
+	setReqFlags(t, func() { reqIDs = naddr })
+	if _, _, err := buildReqFilter([]string{`{"ids":["` + id + `"]}`}); err == nil {
+		t.Fatalf("expected an naddr with JSON ids to be rejected")
+	}
+}
+
+func TestBuildReqFilterTurnsNaddrTagIntoA(t *testing.T) {
+	author := testPubKey(t)
+	naddr, err := nostrkeys.EncodeAddress(author, 30023, "my-article", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := strings.Repeat("cd", 32)
+	setReqFlags(t, func() { reqE = naddr + "," + id })
+
+	filter, _, err := buildReqFilter([]string{"{}"})
+	if err != nil {
+		t.Fatalf("build filter: %v", err)
+	}
+	expected := nostrlib.TagMap{"e": {id}, "a": {"30023:" + author + ":my-article"}}
+	if !reflect.DeepEqual(filter.Tags, expected) || len(filter.Kinds) != 0 {
+		t.Fatalf("unexpected filter %+v", filter)
+	}
+}
+
+func TestBuildReqFilterRejectsEmptyFilter(t *testing.T) {
+	setReqFlags(t, func() {})
+	if _, _, err := buildReqFilter([]string{"{}"}); err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Fatalf("expected an empty filter error, got %v", err)
+	}
+}
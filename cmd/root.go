@@ -34,8 +34,23 @@ func init() {
 	rootCmd.AddCommand(relaysCmd)
 	rootCmd.AddCommand(genKeysCmd)
 	rootCmd.AddCommand(whoamiCmd)
+	rootCmd.AddCommand(encodeCmd)
+	rootCmd.AddCommand(decodeCmd)
 	rootCmd.AddCommand(profileCmd)
 	rootCmd.AddCommand(getProfileCmd)
+	rootCmd.AddCommand(followsCmd)
+	rootCmd.AddCommand(listsCmd)
+	rootCmd.AddCommand(feedCmd)
+	rootCmd.AddCommand(threadCmd)
+	rootCmd.AddCommand(reactCmd)
+	rootCmd.AddCommand(reactionsCmd)
+	rootCmd.AddCommand(repostCmd)
+	rootCmd.AddCommand(deleteCmd)
+	rootCmd.AddCommand(streamCmd)
+	rootCmd.AddCommand(eventCmd)
+	rootCmd.AddCommand(reqCmd)
 	rootCmd.AddCommand(profileManagerCmd)
+	rootCmd.AddCommand(queueCmd)
+	rootCmd.AddCommand(nip05Cmd)
 	registerProfileFlag(rootCmd)
 }
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/spf13/cobra"
+
+	"nostr-cli/internal/relay"
+)
+
+var (
+	streamKinds   string
+	streamAuthors string
+	streamTags    stringListFlag
+	streamSince   string
+	streamJSON    bool
+)
+
+var streamCmd = &cobra.Command{
+	Use:   "stream",
+	Short: "Print live events from your relays as they arrive",
+	Long:  "Open persistent subscriptions on every configured relay and write each new event to stdout, reconnecting when a relay drops. Press Ctrl+C to stop.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+
+		filter, hints, err := buildStreamFilter()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		handlers := relay.StreamHandlers{
+			OnEvent: func(relayURL string, ev *nostrlib.Event) {
+				if streamJSON {
+					line, err := json.Marshal(ev)
+					if err != nil {
+						return
+					}
+					fmt.Println(string(line))
+					return
+				}
+				fmt.Printf("%s · %s · kind %d · %s\n", formatTimestamp(ev.CreatedAt), authorLabel(nil, ev.PubKey), ev.Kind, shortID(ev.ID))
+				fmt.Println(strings.TrimSpace(ev.Content))
+				fmt.Println()
+			},
+			OnError: func(relayURL string, err error) {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", relayURL, err)
+			},
+		}
+		auth := relayAuth(profile, "")
+		relays := withRelayHints(profile.Relays, hints)
+		if len(filter.Authors) > 0 {
+			relays = outboxRelays(ctx, relays, filter.Authors, auth)
+		}
+		return relay.Stream(ctx, relays, filter, auth, handlers)
+	},
+}
+
+func init() {
+	streamCmd.Flags().StringVar(&streamKinds, "kinds", "1", "Comma-separated event kinds")
+	streamCmd.Flags().StringVar(&streamAuthors, "authors", "", "Comma-separated author public keys, npubs, nprofiles or nip05 addresses")
+	streamCmd.Flags().Var(&streamTags, "tag", "Tag filter such as t=bitcoin (repeatable)")
+	streamCmd.Flags().StringVar(&streamSince, "since", "", "Also replay events after this time (unix, date, or duration like 1h)")
+	streamCmd.Flags().BoolVar(&streamJSON, "json", false, "Print one JSON event per line")
+	registerProfileFlag(streamCmd)
+}
+
+func buildStreamFilter() (nostrlib.Filter, []string, error) {
+	authors, hints, err := resolvePubKeys(context.Background(), splitList(streamAuthors))
+	if err != nil {
+		return nostrlib.Filter{}, nil, err
+	}
+	filter := nostrlib.Filter{Authors: authors}
+
+	kinds, err := parseKinds(streamKinds)
+	if err != nil {
+		return filter, nil, err
+	}
+	filter.Kinds = kinds
+
+	if filter.Tags, err = parseTagFilters(streamTags); err != nil {
+		return filter, nil, err
+	}
+
+	since, err := parseTimeFlag("since", streamSince)
+	if err != nil {
+		return filter, nil, err
+	}
+	if since == nil {
+		now := nostrlib.Now()
+		since = &now
+	}
+	filter.Since = since
+	return filter, hints, nil
+}
+
+func parseKinds(value string) ([]int, error) {
+	var kinds []int
+	for _, item := range splitList(value) {
+		kind, err := strconv.Atoi(item)
+		if err != nil || kind < 0 {
+			return nil, fmt.Errorf("invalid kind %q", item)
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
+
+func parseTagFilters(values []string) (nostrlib.TagMap, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	tags := make(nostrlib.TagMap)
+	for _, value := range values {
+		name, tagValue, ok := strings.Cut(value, "=")
+		name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+		tagValue = strings.TrimSpace(tagValue)
+		if !ok || len(name) != 1 || tagValue == "" {
+			return nil, fmt.Errorf("invalid tag filter %q: use a single-letter name like t=bitcoin", value)
+		}
+		tags[name] = append(tags[name], tagValue)
+	}
+	return tags, nil
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/spf13/cobra"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip10"
+)
+
+var threadJSON bool
+
+type threadItem struct {
+	ID        string             `json:"id"`
+	PubKey    string             `json:"pubkey"`
+	Author    string             `json:"author,omitempty"`
+	CreatedAt nostrlib.Timestamp `json:"created_at"`
+	Content   string             `json:"content"`
+	Replies   []threadItem       `json:"replies"`
+}
+
+var threadCmd = &cobra.Command{
+	Use:   "thread <note>",
+	Short: "Show a conversation as a reply tree",
+	Long:  "Fetch the root of the thread a note belongs to and every reply to it from your relays, any relay hints, and the read relays of the people in the thread, then print the discussion as an indented tree.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, profile, _, err := loadProfileForCommand()
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		auth := relayAuth(profile, "")
+
+		ref, err := resolveEventRef(args[0])
+		if err != nil {
+			return err
+		}
+		relays := withRelayHints(profile.Relays, ref.Relays)
+		target, _, err := fetchReferencedEvent(ctx, relays, args[0], auth)
+		if err != nil {
+			return err
+		}
+
+		root := target
+		if tag := nip10.RootTag(target); tag != nil {
+			if len(tag) >= 3 && tag[2] != "" {
+				relays = withRelayHints(relays, []string{tag[2]})
+			}
+			fetched, err := relay.FetchEvent(ctx, relays, tag[1], auth)
+			switch {
+			case err == nil:
+				root = fetched
+			case errors.Is(err, relay.ErrNotFound):
+				fmt.Fprintf(os.Stderr, "Root %s was not found; showing the thread from the requested note.\n", shortID(tag[1]))
+			default:
+				return err
+			}
+		}
+
+		// replies are delivered to the inboxes of the people in the thread
+		relays = withRelayHints(relays, inboxRelays(ctx, relays, []string{root.PubKey, target.PubKey}, auth))
+		replies, err := relay.QueryRelays(ctx, relays, nostrlib.Filter{Kinds: []int{1}, Tags: nostrlib.TagMap{"e": []string{root.ID}}}, auth)
+		if err != nil {
+			return err
+		}
+		if root != target {
+			// replies to the requested note that never tagged the root
+			more, err := relay.QueryRelays(ctx, relays, nostrlib.Filter{Kinds: []int{1}, Tags: nostrlib.TagMap{"e": []string{target.ID}}}, auth)
+			if err == nil {
+				replies = append(replies, more...)
+			}
+			replies = append(replies, target)
+		}
+		var verified []*nostrlib.Event
+		for _, ev := range replies {
+			if ok, _ := ev.CheckSignature(); ok {
+				verified = append(verified, ev)
+			}
+		}
+		tree := nip10.BuildTree(root, verified)
+
+		pubKeys := []string{}
+		collectThreadAuthors(tree, &pubKeys)
+		names := resolveDisplayNames(ctx, relays, pubKeys, auth)
+
+		if threadJSON {
+			output, err := json.MarshalIndent(threadJSONItem(tree, names), "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(output))
+			return nil
+		}
+		printThread(tree, names, target.ID, 0)
+		return nil
+	},
+}
+
+func collectThreadAuthors(node *nip10.Node, pubKeys *[]string) {
+	*pubKeys = append(*pubKeys, node.Event.PubKey)
+	for _, reply := range node.Replies {
+		collectThreadAuthors(reply, pubKeys)
+	}
+}
+
+func threadJSONItem(node *nip10.Node, names map[string]string) threadItem {
+	item := threadItem{
+		ID:        node.Event.ID,
+		PubKey:    node.Event.PubKey,
+		Author:    names[node.Event.PubKey],
+		CreatedAt: node.Event.CreatedAt,
+		Content:   node.Event.Content,
+		Replies:   []threadItem{},
+	}
+	for _, reply := range node.Replies {
+		item.Replies = append(item.Replies, threadJSONItem(reply, names))
+	}
+	return item
+}
+
+func printThread(node *nip10.Node, names map[string]string, highlight string, depth int) {
+	indent := strings.Repeat("  ", depth)
+	marker := ""
+	if node.Event.ID == highlight {
+		marker = " ◀"
+	}
+	fmt.Printf("%s%s · %s · %s%s\n", indent, authorLabel(names, node.Event.PubKey), formatTimestamp(node.Event.CreatedAt), shortID(node.Event.ID), marker)
+	for _, line := range strings.Split(strings.TrimSpace(node.Event.Content), "\n") {
+		fmt.Printf("%s  %s\n", indent, line)
+	}
+	fmt.Println()
+	for _, reply := range node.Replies {
+		printThread(reply, names, highlight, depth+1)
+	}
+}
+
+func init() {
+	threadCmd.Flags().BoolVar(&threadJSON, "json", false, "Print the reply tree as JSON")
+	registerProfileFlag(threadCmd)
+}
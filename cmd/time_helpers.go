@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+// parseTimeFlag accepts unix seconds, RFC3339 or YYYY-MM-DD dates, and
+// durations such as 24h that count back from now.
+func parseTimeFlag(name, value string) (*nostrlib.Timestamp, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if unix, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+		ts := nostrlib.Timestamp(unix)
+		return &ts, nil
+	}
+	if d, err := time.ParseDuration(trimmed); err == nil {
+		ts := nostrlib.Timestamp(time.Now().Add(-d).Unix())
+		return &ts, nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, trimmed, time.Local); err == nil {
+			ts := nostrlib.Timestamp(t.Unix())
+			return &ts, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid --%s value %q: use unix seconds, a date, or a duration like 24h", name, value)
+}
+
+func formatTimestamp(ts nostrlib.Timestamp) string {
+	return ts.Time().Local().Format("2006-01-02 15:04")
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		trimmed := strings.TrimSpace(item)
+		if trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
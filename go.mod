@@ -4,10 +4,12 @@ go 1.21
 
 require (
 github.com/btcsuite/btcd/btcutil v1.1.3
+github.com/gobwas/ws v1.2.0
 github.com/nbd-wtf/go-nostr v0.27.5
 github.com/spf13/cobra v1.7.0
 golang.org/x/crypto v0.16.0
 golang.org/x/term v0.15.0
+gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -17,7 +19,6 @@ require (
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.2.0 // indirect
 	github.com/gobwas/httphead v0.1.0 // indirect
 	github.com/gobwas/pool v0.2.1 // indirect
-	github.com/gobwas/ws v1.2.0 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/puzpuzpuz/xsync/v2 v2.5.1 // indirect
@@ -0,0 +1,257 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	"nostr-cli/internal/fileutil"
+	"nostr-cli/internal/relay"
+	nostrkeys "nostr-cli/nostr"
+)
+
+type Entry struct {
+	ID        string         `json:"id"`
+	Event     nostrlib.Event `json:"event"`
+	Relays    []string       `json:"relays"`
+	QueuedAt  time.Time      `json:"queued_at"`
+	Attempts  int            `json:"attempts"`
+	LastError string         `json:"last_error,omitempty"`
+}
+
+type FlushResult struct {
+	Entry   Entry
+	Results []relay.PublishResult
+	Done    bool
+}
+
+func GetQueuePath() string {
+	return filepath.Join(filepath.Dir(nostrkeys.GetConfigPath()), "queue.json")
+}
+
+func Load() ([]Entry, error) {
+	data, err := os.ReadFile(GetQueuePath())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading queue: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing queue: %w", err)
+	}
+	return entries, nil
+}
+
+// Save replaces the queue file atomically, so readers never see a partly
+// written queue. Callers that loaded the entries first should hold the lock.
+func Save(entries []Entry) error {
+	if entries == nil {
+		entries = []Entry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(GetQueuePath(), data)
+}
+
+// update runs change on the current entries and saves the result while
+// holding the queue lock, so concurrent commands cannot overwrite each other.
+func update(change func([]Entry) ([]Entry, error)) error {
+	unlock, err := fileutil.Lock(GetQueuePath() + ".lock")
+	if err != nil {
+		return fmt.Errorf("locking queue: %w", err)
+	}
+	defer unlock()
+
+	entries, err := Load()
+	if err != nil {
+		return err
+	}
+	entries, err = change(entries)
+	if err != nil {
+		return err
+	}
+	return Save(entries)
+}
+
+// PendingRelays lists relays that did not accept the event but might later,
+// including ones that asked for authentication.
+func PendingRelays(results []relay.PublishResult) []string {
+	var pending []string
+	for _, result := range results {
+		if !result.Accepted && (result.Retryable || result.AuthRequired()) {
+			pending = append(pending, result.Relay)
+		}
+	}
+	return pending
+}
+
+// Queueable reports whether events of kind are kept for relays that missed
+// them: only notes and articles. A profile, follow list or other list resent
+// later could replace a newer version published elsewhere in the meantime.
+func Queueable(kind int) bool {
+	return kind == 1 || kind == 30023
+}
+
+// Add queues a note or article for the relays that did not accept it. Other
+// kinds are never queued.
+func Add(ev nostrlib.Event, results []relay.PublishResult) (*Entry, error) {
+	if !Queueable(ev.Kind) {
+		return nil, nil
+	}
+	pending := PendingRelays(results)
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	entry := Entry{
+		ID:        ev.ID,
+		Event:     ev,
+		Relays:    pending,
+		QueuedAt:  time.Now().UTC(),
+		Attempts:  1,
+		LastError: lastError(results),
+	}
+	err := update(func(entries []Entry) ([]Entry, error) {
+		for i := range entries {
+			if entries[i].ID == ev.ID {
+				entry.QueuedAt = entries[i].QueuedAt
+				entry.Attempts = entries[i].Attempts + 1
+				entries[i] = entry
+				return entries, nil
+			}
+		}
+		return append(entries, entry), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func Drop(id string) (*Entry, error) {
+	var dropped Entry
+	err := update(func(entries []Entry) ([]Entry, error) {
+		index, err := find(entries, id)
+		if err != nil {
+			return nil, err
+		}
+		dropped = entries[index]
+		return append(entries[:index], entries[index+1:]...), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dropped, nil
+}
+
+// Flush resends every queued event to the relays that still need it. Relays
+// that accept are removed from the entry and finished entries are dropped.
+// The queue is not locked while publishing; the results are merged into the
+// queue as it is afterwards, keeping entries other commands changed meanwhile.
+func Flush(ctx context.Context, policy relay.Policy) ([]FlushResult, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var flushed []FlushResult
+	for _, entry := range entries {
+		results, _ := relay.PublishToRelays(ctx, entry.Relays, entry.Event, policy)
+		entry.Attempts++
+		entry.Relays = PendingRelays(results)
+		entry.LastError = lastError(results)
+		flushed = append(flushed, FlushResult{Entry: entry, Results: results, Done: len(entry.Relays) == 0})
+	}
+
+	err = update(func(current []Entry) ([]Entry, error) {
+		return merge(current, entries, flushed), nil
+	})
+	return flushed, err
+}
+
+// merge applies flush results to the current queue. Relays queued for an
+// entry after the flush started are kept, and entries dropped meanwhile stay
+// dropped.
+func merge(current, sent []Entry, flushed []FlushResult) []Entry {
+	var merged []Entry
+	for _, entry := range current {
+		index := -1
+		for i := range flushed {
+			if flushed[i].Entry.ID == entry.ID {
+				index = i
+				break
+			}
+		}
+		if index == -1 {
+			merged = append(merged, entry)
+			continue
+		}
+
+		relays := flushed[index].Entry.Relays
+		for _, url := range entry.Relays {
+			if !containsRelay(sent[index].Relays, url) && !containsRelay(relays, url) {
+				relays = append(relays, url)
+			}
+		}
+		if len(relays) == 0 {
+			continue
+		}
+		updated := flushed[index].Entry
+		updated.Relays = relays
+		if entry.Attempts >= updated.Attempts {
+			updated.Attempts = entry.Attempts + 1
+		}
+		merged = append(merged, updated)
+	}
+	return merged
+}
+
+func containsRelay(relays []string, url string) bool {
+	for _, candidate := range relays {
+		if candidate == url {
+			return true
+		}
+	}
+	return false
+}
+
+func find(entries []Entry, id string) (int, error) {
+	prefix := strings.ToLower(strings.TrimSpace(id))
+	if prefix == "" {
+		return -1, errors.New("a queued event ID is required")
+	}
+	match := -1
+	for i, entry := range entries {
+		if !strings.HasPrefix(entry.ID, prefix) {
+			continue
+		}
+		if match != -1 {
+			return -1, fmt.Errorf("'%s' matches more than one queued event", id)
+		}
+		match = i
+	}
+	if match == -1 {
+		return -1, fmt.Errorf("no queued event matches '%s'", id)
+	}
+	return match, nil
+}
+
+func lastError(results []relay.PublishResult) string {
+	for _, result := range results {
+		if !result.Accepted && result.Err != nil {
+			return fmt.Sprintf("%s: %v", result.Relay, result.Err)
+		}
+	}
+	return ""
+}
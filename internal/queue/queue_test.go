@@ -0,0 +1,287 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	"nostr-cli/internal/fileutil"
+	"nostr-cli/internal/relay"
+)
+
+// newRelay serves a relay that answers every EVENT with an OK carrying
+// reason, accepting the event when reason is empty.
+func newRelay(t *testing.T, reason string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _, err := ws.UpgradeHTTP(r, w)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			msg, err := wsutil.ReadClientText(conn)
+			if err != nil {
+				return
+			}
+			if env, ok := nostrlib.ParseMessage(msg).(*nostrlib.EventEnvelope); ok {
+				data, _ := (&nostrlib.OKEnvelope{EventID: env.Event.ID, OK: reason == "", Reason: reason}).MarshalJSON()
+				_ = wsutil.WriteServerText(conn, data)
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func signedEvent(t *testing.T, content string) nostrlib.Event {
+	t.Helper()
+	ev := nostrlib.Event{Kind: 1, Content: content, CreatedAt: nostrlib.Now(), Tags: nostrlib.Tags{}}
+	if err := ev.Sign(nostrlib.GeneratePrivateKey()); err != nil {
+		t.Fatal(err)
+	}
+	return ev
+}
+
+func failed(url string, retryable bool, message string) relay.PublishResult {
+	return relay.PublishResult{Relay: url, Retryable: retryable, Message: message, Err: errors.New("failed")}
+}
+
+func TestAddQueuesPendingRelays(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ev := signedEvent(t, "queued")
+	results := []relay.PublishResult{
+		{Relay: "wss://accepted.example", Accepted: true},
+		failed("wss://down.example", true, ""),
+		failed("wss://blocked.example", false, "blocked: no"),
+		failed("wss://members.example", false, "auth-required: members only"),
+	}
+
+	entry, err := Add(ev, results)
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	expected := []string{"wss://down.example", "wss://members.example"}
+	if entry == nil || !reflect.DeepEqual(entry.Relays, expected) || entry.Attempts != 1 {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+
+	if entry, err := Add(signedEvent(t, "delivered"), results[:1]); err != nil || entry != nil {
+		t.Fatalf("expected nothing queued for a delivered event, got %+v, %v", entry, err)
+	}
+	entries, err := Load()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one queued entry, got %+v, %v", entries, err)
+	}
+}
+
+func TestAddSkipsReplaceableKinds(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	for _, kind := range []int{0, 3, 7, 10002, 30000} {
+		ev := nostrlib.Event{Kind: kind, CreatedAt: nostrlib.Now(), Tags: nostrlib.Tags{}}
+		if err := ev.Sign(nostrlib.GeneratePrivateKey()); err != nil {
+			t.Fatal(err)
+		}
+		if entry, err := Add(ev, []relay.PublishResult{failed("wss://down.example", true, "")}); err != nil || entry != nil {
+			t.Fatalf("expected kind %d not to be queued, got %+v, %v", kind, entry, err)
+		}
+	}
+	if entries, err := Load(); err != nil || len(entries) != 0 {
+		t.Fatalf("expected an empty queue, got %+v, %v", entries, err)
+	}
+}
+
+func TestAddReplacesExistingEntry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ev := signedEvent(t, "again")
+	first, err := Add(ev, []relay.PublishResult{failed("wss://a.example", true, ""), failed("wss://b.example", true, "")})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	second, err := Add(ev, []relay.PublishResult{failed("wss://b.example", true, "")})
+	if err != nil {
+		t.Fatalf("add again: %v", err)
+	}
+	if second.Attempts != 2 || !second.QueuedAt.Equal(first.QueuedAt) || !reflect.DeepEqual(second.Relays, []string{"wss://b.example"}) {
+		t.Fatalf("unexpected replacement %+v (first queued %s)", second, first.QueuedAt)
+	}
+	entries, err := Load()
+	if err != nil || len(entries) != 1 || entries[0].Attempts != 2 {
+		t.Fatalf("expected the entry to be replaced, got %+v, %v", entries, err)
+	}
+}
+
+func TestSaveReplacesFileAtomically(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	if err := Save([]Entry{{ID: "a"}, {ID: "b"}}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if err := Save([]Entry{{ID: "c"}}); err != nil {
+		t.Fatalf("save again: %v", err)
+	}
+
+	entries, err := Load()
+	if err != nil || len(entries) != 1 || entries[0].ID != "c" {
+		t.Fatalf("unexpected queue %+v, %v", entries, err)
+	}
+	info, err := os.Stat(GetQueuePath())
+	if err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected a private queue file, got %v, %v", info, err)
+	}
+	files, err := os.ReadDir(filepath.Dir(GetQueuePath()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected only the queue file, got %v", files)
+	}
+}
+
+func TestUpdateWaitsForLock(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	unlock, err := fileutil.Lock(GetQueuePath() + ".lock")
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+	released := make(chan struct{})
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		close(released)
+		unlock()
+	}()
+
+	if _, err := Add(signedEvent(t, "waiting"), []relay.PublishResult{failed("wss://down.example", true, "")}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	select {
+	case <-released:
+	default:
+		t.Fatalf("add did not wait for the lock")
+	}
+	if _, err := os.Stat(GetQueuePath() + ".lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the lock to be released, got %v", err)
+	}
+}
+
+func TestUpdateTakesOverStaleLock(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	lockPath := GetQueuePath() + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lockPath, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * fileutil.StaleLockAge)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Add(signedEvent(t, "stale"), []relay.PublishResult{failed("wss://down.example", true, "")}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if entries, err := Load(); err != nil || len(entries) != 1 {
+		t.Fatalf("expected one queued entry, got %+v, %v", entries, err)
+	}
+}
+
+func TestDropMatchesIDPrefix(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	entries := []Entry{
+		{ID: "abc123", Relays: []string{"wss://a.example"}},
+		{ID: "abd456", Relays: []string{"wss://a.example"}},
+	}
+	if err := Save(entries); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	if _, err := Drop("ab"); err == nil || !strings.Contains(err.Error(), "more than one") {
+		t.Fatalf("expected an ambiguous prefix error, got %v", err)
+	}
+	if _, err := Drop("xyz"); err == nil {
+		t.Fatalf("expected an error for an unknown prefix")
+	}
+	dropped, err := Drop(" ABD ")
+	if err != nil || dropped.ID != "abd456" {
+		t.Fatalf("expected abd456 to be dropped, got %+v, %v", dropped, err)
+	}
+	remaining, err := Load()
+	if err != nil || len(remaining) != 1 || remaining[0].ID != "abc123" {
+		t.Fatalf("unexpected queue after drop %+v, %v", remaining, err)
+	}
+}
+
+func TestFlushRemovesOnlyAcceptedRelays(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	accepting := newRelay(t, "")
+	members := newRelay(t, "auth-required: members only")
+	closed := httptest.NewServer(http.NotFoundHandler())
+	down := "ws" + strings.TrimPrefix(closed.URL, "http")
+	closed.Close()
+
+	partialEvent, doneEvent := signedEvent(t, "partial"), signedEvent(t, "done")
+	partial := Entry{ID: partialEvent.ID, Event: partialEvent, Relays: []string{accepting, down, members}, Attempts: 1}
+	done := Entry{ID: doneEvent.ID, Event: doneEvent, Relays: []string{accepting}, Attempts: 1}
+	if err := Save([]Entry{partial, done}); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	policy := relay.DefaultPolicy()
+	policy.Retries = 0
+	flushed, err := Flush(context.Background(), policy)
+	if err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if len(flushed) != 2 || flushed[0].Done || !flushed[1].Done {
+		t.Fatalf("unexpected flush results %+v", flushed)
+	}
+
+	remaining, err := Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].ID != partial.ID || remaining[0].Attempts != 2 {
+		t.Fatalf("unexpected queue after flush %+v", remaining)
+	}
+	if !reflect.DeepEqual(remaining[0].Relays, []string{down, members}) {
+		t.Fatalf("expected only the accepting relay to be removed, got %v", remaining[0].Relays)
+	}
+}
+
+func TestMergeKeepsConcurrentChanges(t *testing.T) {
+	sent := []Entry{
+		{ID: "a", Relays: []string{"wss://1", "wss://2"}, Attempts: 1},
+		{ID: "b", Relays: []string{"wss://1"}, Attempts: 1},
+	}
+	flushed := []FlushResult{
+		{Entry: Entry{ID: "a", Relays: nil, Attempts: 2}, Done: true},
+		{Entry: Entry{ID: "b", Relays: []string{"wss://1"}, Attempts: 2}},
+	}
+	// while flushing, a was queued again for another relay, b was dropped and
+	// c was added
+	current := []Entry{
+		{ID: "a", Relays: []string{"wss://1", "wss://3"}, Attempts: 2},
+		{ID: "c", Relays: []string{"wss://1"}, Attempts: 1},
+	}
+
+	merged := merge(current, sent, flushed)
+	if len(merged) != 2 || merged[0].ID != "a" || merged[1].ID != "c" {
+		t.Fatalf("unexpected merge %+v", merged)
+	}
+	if !reflect.DeepEqual(merged[0].Relays, []string{"wss://3"}) || merged[0].Attempts != 3 {
+		t.Fatalf("unexpected merged entry %+v", merged[0])
+	}
+}
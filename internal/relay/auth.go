@@ -0,0 +1,46 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+// Auth answers NIP-42 challenges, but only for relays the profile opted into.
+type Auth struct {
+	Relays []string
+	Sign   func(ev *nostrlib.Event) error
+}
+
+func (a *Auth) enabled(url string) bool {
+	if a == nil || a.Sign == nil {
+		return false
+	}
+	key := relayKey(url)
+	for _, candidate := range a.Relays {
+		if relayKey(candidate) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *Auth) authenticate(ctx context.Context, conn *nostrlib.Relay) error {
+	if err := conn.Auth(ctx, a.Sign); err != nil {
+		if message, ok := okMessage(err); ok {
+			return fmt.Errorf("authentication rejected: %s", message)
+		}
+		return fmt.Errorf("authenticating: %w", err)
+	}
+	return nil
+}
+
+func authRequired(message string) bool {
+	return hasPrefix(message, "auth-required")
+}
+
+func relayKey(url string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(url), "/"))
+}
@@ -0,0 +1,215 @@
+package relay
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip42"
+)
+
+const (
+	testChallenge = "stand-in-challenge"
+	dropReply     = "drop"
+)
+
+// authRelay is a minimal relay that rejects EVENT and REQ until the client
+// answers its AUTH challenge. With replies set it skips auth and answers each
+// EVENT with the next reply as the OK reason, accepting on an empty one,
+// hanging up without an answer on dropReply and repeating the last one when
+// they run out.
+type authRelay struct {
+	mu       sync.Mutex
+	events   []nostrlib.Event
+	authed   []string
+	replies  []string
+	attempts int
+}
+
+func (s *authRelay) nextReply() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	reply := s.replies[len(s.replies)-1]
+	if s.attempts <= len(s.replies) {
+		reply = s.replies[s.attempts-1]
+	}
+	return reply
+}
+
+func (s *authRelay) handle(w http.ResponseWriter, r *http.Request) {
+	conn, _, _, err := ws.UpgradeHTTP(r, w)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	url := "ws://" + r.Host
+	authed := false
+	send := func(env nostrlib.Envelope) {
+		data, _ := env.MarshalJSON()
+		_ = wsutil.WriteServerText(conn, data)
+	}
+	// go-nostr drops frames that arrive together with the handshake
+	// response, so the challenge is sent right before the first rejection.
+	challenged := false
+	challenge := func() {
+		if !challenged {
+			challenged = true
+			value := testChallenge
+			send(&nostrlib.AuthEnvelope{Challenge: &value})
+		}
+	}
+
+	for {
+		msg, err := wsutil.ReadClientText(conn)
+		if err != nil {
+			return
+		}
+		switch env := nostrlib.ParseMessage(msg).(type) {
+		case *nostrlib.AuthEnvelope:
+			pubkey, ok := nip42.ValidateAuthEvent(&env.Event, testChallenge, url)
+			if ok {
+				authed = true
+				s.mu.Lock()
+				s.authed = append(s.authed, pubkey)
+				s.mu.Unlock()
+			}
+			send(&nostrlib.OKEnvelope{EventID: env.Event.ID, OK: ok, Reason: "invalid: bad auth"})
+		case *nostrlib.EventEnvelope:
+			if len(s.replies) > 0 {
+				reply := s.nextReply()
+				if reply == dropReply {
+					return
+				}
+				send(&nostrlib.OKEnvelope{EventID: env.Event.ID, OK: reply == "", Reason: reply})
+				continue
+			}
+			if !authed {
+				challenge()
+				send(&nostrlib.OKEnvelope{EventID: env.Event.ID, OK: false, Reason: "auth-required: members only"})
+				continue
+			}
+			s.mu.Lock()
+			s.events = append(s.events, env.Event)
+			s.mu.Unlock()
+			send(&nostrlib.OKEnvelope{EventID: env.Event.ID, OK: true})
+		case *nostrlib.ReqEnvelope:
+			if !authed {
+				challenge()
+				send(&nostrlib.ClosedEnvelope{SubscriptionID: env.SubscriptionID, Reason: "auth-required: members only"})
+				continue
+			}
+			s.mu.Lock()
+			stored := append([]nostrlib.Event{}, s.events...)
+			s.mu.Unlock()
+			for _, ev := range stored {
+				if env.Filters.Match(&ev) {
+					id := env.SubscriptionID
+					send(&nostrlib.EventEnvelope{SubscriptionID: &id, Event: ev})
+				}
+			}
+			eose := nostrlib.EOSEEnvelope(env.SubscriptionID)
+			send(&eose)
+		}
+	}
+}
+
+func newAuthRelay(t *testing.T) (*authRelay, string) {
+	t.Helper()
+	stand := &authRelay{}
+	server := httptest.NewServer(http.HandlerFunc(stand.handle))
+	t.Cleanup(server.Close)
+	return stand, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func newScriptedRelay(t *testing.T, replies ...string) (*authRelay, string) {
+	t.Helper()
+	stand := &authRelay{replies: replies}
+	server := httptest.NewServer(http.HandlerFunc(stand.handle))
+	t.Cleanup(server.Close)
+	return stand, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func testAuth(t *testing.T) (string, func(urls ...string) *Auth) {
+	t.Helper()
+	sk := nostrlib.GeneratePrivateKey()
+	pk, err := nostrlib.GetPublicKey(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pk, func(urls ...string) *Auth {
+		return &Auth{Relays: urls, Sign: func(ev *nostrlib.Event) error {
+			ev.PubKey = pk
+			return ev.Sign(sk)
+		}}
+	}
+}
+
+func signedEvent(t *testing.T, kind int, content string) nostrlib.Event {
+	t.Helper()
+	sk := nostrlib.GeneratePrivateKey()
+	ev := nostrlib.Event{CreatedAt: nostrlib.Now(), Kind: kind, Content: content}
+	ev.PubKey, _ = nostrlib.GetPublicKey(sk)
+	if err := ev.Sign(sk); err != nil {
+		t.Fatal(err)
+	}
+	return ev
+}
+
+func TestPublishAuthenticatesOptedInRelay(t *testing.T) {
+	stand, url := newAuthRelay(t)
+	authPubKey, newAuth := testAuth(t)
+	ev := signedEvent(t, 1, "members only")
+
+	policy := DefaultPolicy()
+	policy.Retries = 0
+	results, err := PublishToRelays(context.Background(), []string{url}, ev, policy)
+	if err == nil || results[0].Accepted {
+		t.Fatalf("expected rejection without auth, got %+v", results[0])
+	}
+	if !authRequired(results[0].Message) {
+		t.Fatalf("expected auth-required message, got %q", results[0].Message)
+	}
+
+	policy.Auth = newAuth(url + "/")
+	results, err = PublishToRelays(context.Background(), []string{url}, ev, policy)
+	if err != nil {
+		t.Fatalf("expected publish to succeed after auth: %v", err)
+	}
+	if !results[0].Accepted {
+		t.Fatalf("expected relay to accept event, got %+v", results[0])
+	}
+
+	stand.mu.Lock()
+	defer stand.mu.Unlock()
+	if len(stand.authed) != 1 || stand.authed[0] != authPubKey {
+		t.Fatalf("expected one auth as %s, got %v", authPubKey, stand.authed)
+	}
+}
+
+func TestQueryAuthenticatesOptedInRelay(t *testing.T) {
+	stand, url := newAuthRelay(t)
+	_, newAuth := testAuth(t)
+	ev := signedEvent(t, 0, `{"name":"alice"}`)
+	stand.events = append(stand.events, ev)
+
+	filter := nostrlib.Filter{Kinds: []int{0}, Authors: []string{ev.PubKey}}
+	if _, err := QueryRelay(context.Background(), url, filter, newAuth("wss://elsewhere.example")); err == nil {
+		t.Fatal("expected query to fail for a relay that was not opted in")
+	}
+
+	events, err := QueryRelay(context.Background(), url, filter, newAuth(url))
+	if err != nil {
+		t.Fatalf("query after auth: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != ev.ID {
+		t.Fatalf("expected stored event, got %v", events)
+	}
+}
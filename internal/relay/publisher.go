@@ -2,28 +2,231 @@ package relay
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	nostrlib "github.com/nbd-wtf/go-nostr"
 )
 
-func PublishToRelays(ctx context.Context, relays []string, ev nostrlib.Event) {
-	publishCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+const (
+	DefaultConnectTimeout = 5 * time.Second
+	DefaultOKTimeout      = 7 * time.Second
+	DefaultRetries        = 2
+	DefaultBackoff        = 500 * time.Millisecond
+)
+
+var ErrQuorumNotMet = errors.New("publish quorum not met")
+
+type Policy struct {
+	// MinAcks is how many relays must accept an event; 0 publishes on a best
+	// effort basis and never fails for lack of acceptances.
+	MinAcks        int
+	Retries        int
+	Backoff        time.Duration
+	ConnectTimeout time.Duration
+	OKTimeout      time.Duration
+	Auth           *Auth
+	// Inboxes returns the read relays of the given users, where events that
+	// tag them are delivered as well.
+	Inboxes func(ctx context.Context, pubKeys []string) []string
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MinAcks:        1,
+		Retries:        DefaultRetries,
+		Backoff:        DefaultBackoff,
+		ConnectTimeout: DefaultConnectTimeout,
+		OKTimeout:      DefaultOKTimeout,
+	}
+}
+
+func (p Policy) normalized() Policy {
+	defaults := DefaultPolicy()
+	if p.MinAcks < 0 {
+		p.MinAcks = 0
+	}
+	if p.Retries < 0 {
+		p.Retries = 0
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = defaults.Backoff
+	}
+	if p.ConnectTimeout <= 0 {
+		p.ConnectTimeout = defaults.ConnectTimeout
+	}
+	if p.OKTimeout <= 0 {
+		p.OKTimeout = defaults.OKTimeout
+	}
+	return p
+}
+
+type PublishResult struct {
+	Relay     string
+	Accepted  bool
+	Message   string
+	Latency   time.Duration
+	Attempts  int
+	Retryable bool
+	Err       error
+}
+
+// AuthRequired reports a rejection that authenticating with NIP-42 could
+// turn into an acceptance.
+func (r PublishResult) AuthRequired() bool {
+	return !r.Accepted && authRequired(r.Message)
+}
+
+func PublishToRelays(ctx context.Context, relays []string, ev nostrlib.Event, policy Policy) ([]PublishResult, error) {
+	policy = policy.normalized()
+	targets := uniqueRelays(relays)
+	results := make([]PublishResult, len(targets))
+
+	var wg sync.WaitGroup
+	for i, url := range targets {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			results[i] = publishWithRetries(ctx, url, ev, policy)
+		}(i, url)
+	}
+	wg.Wait()
+
+	if accepted := AcceptedCount(results); accepted < policy.MinAcks {
+		return results, fmt.Errorf("%w: %d of %d required relay(s) accepted the event", ErrQuorumNotMet, accepted, policy.MinAcks)
+	}
+	return results, nil
+}
+
+func AcceptedCount(results []PublishResult) int {
+	count := 0
+	for _, result := range results {
+		if result.Accepted {
+			count++
+		}
+	}
+	return count
+}
+
+func publishWithRetries(ctx context.Context, url string, ev nostrlib.Event, policy Policy) PublishResult {
+	started := time.Now()
+	backoff := policy.Backoff
+
+	var result PublishResult
+	for attempt := 0; ; attempt++ {
+		var retryable bool
+		result, retryable = publishToRelay(ctx, url, ev, policy)
+		result.Attempts = attempt + 1
+		result.Retryable = retryable
+		if result.Accepted || !retryable || attempt >= policy.Retries || ctx.Err() != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			result.Err = ctx.Err()
+			result.Latency = time.Since(started)
+			return result
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	result.Latency = time.Since(started)
+	return result
+}
+
+func publishToRelay(ctx context.Context, url string, ev nostrlib.Event, policy Policy) (PublishResult, bool) {
+	result := PublishResult{Relay: url}
+
+	connectCtx, cancel := context.WithTimeout(ctx, policy.ConnectTimeout)
+	conn, err := nostrlib.RelayConnect(connectCtx, url)
+	cancel()
+	if err != nil {
+		result.Err = fmt.Errorf("connecting: %w", err)
+		return result, true
+	}
+	defer conn.Close()
+
+	okCtx, cancel := context.WithTimeout(ctx, policy.OKTimeout)
 	defer cancel()
+	err = publish(okCtx, conn, ev)
+	if message, ok := okMessage(err); ok && authRequired(message) && policy.Auth.enabled(url) {
+		if err := policy.Auth.authenticate(okCtx, conn); err != nil {
+			result.Message = message
+			result.Err = err
+			return result, false
+		}
+		err = publish(okCtx, conn, ev)
+	}
+	if err != nil {
+		message, ok := okMessage(err)
+		if !ok {
+			result.Err = err
+			return result, true
+		}
+		result.Message = message
+		if hasPrefix(message, "duplicate") {
+			result.Accepted = true
+			return result, false
+		}
+		result.Err = fmt.Errorf("rejected: %s", message)
+		return result, retryableRejection(message)
+	}
 
+	result.Accepted = true
+	return result, false
+}
+
+// publish sends ev and waits for the relay's OK. go-nostr also returns nil
+// when the connection closes before any OK arrives, so nil only counts as an
+// acceptance while the connection is still up.
+func publish(ctx context.Context, conn *nostrlib.Relay, ev nostrlib.Event) error {
+	err := conn.Publish(ctx, ev)
+	if err == nil && conn.Context().Err() != nil {
+		return errors.New("connection closed before the relay answered")
+	}
+	return err
+}
+
+// go-nostr reports OK false replies as "msg: <reason>".
+func okMessage(err error) (string, bool) {
+	if err == nil {
+		return "", false
+	}
+	text := err.Error()
+	if !strings.HasPrefix(text, "msg: ") {
+		return "", false
+	}
+	return strings.TrimPrefix(text, "msg: "), true
+}
+
+// pow:, blocked:, invalid: and restricted: rejections won't change on resend.
+func retryableRejection(message string) bool {
+	return hasPrefix(message, "rate-limited") || hasPrefix(message, "error")
+}
+
+func hasPrefix(message, prefix string) bool {
+	return strings.HasPrefix(strings.TrimSpace(message), prefix+":")
+}
+
+func uniqueRelays(relays []string) []string {
+	seen := make(map[string]struct{})
+	var unique []string
 	for _, url := range relays {
-		relay, err := nostrlib.RelayConnect(publishCtx, url)
-		if err != nil {
-			fmt.Printf("Failed to connect to %s: %v\n", url, err)
+		trimmed := strings.TrimRight(strings.TrimSpace(url), "/")
+		if trimmed == "" {
 			continue
 		}
-
-		if err := relay.Publish(publishCtx, ev); err != nil {
-			fmt.Printf("Failed to publish to %s: %v\n", url, err)
-		} else {
-			fmt.Printf("Published to %s\n", url)
+		key := relayKey(trimmed)
+		if _, ok := seen[key]; ok {
+			continue
 		}
-		relay.Close()
+		seen[key] = struct{}{}
+		unique = append(unique, trimmed)
 	}
+	return unique
 }
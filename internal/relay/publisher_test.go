@@ -0,0 +1,177 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPublishToRelays(t *testing.T) {
+	tests := []struct {
+		name         string
+		replies      [][]string
+		minAcks      int
+		retries      int
+		wantQuorum   bool
+		wantAccepted []bool
+		wantAttempts []int
+		wantRetry    []bool
+		minLatency   time.Duration
+	}{
+		{
+			name:         "accepted",
+			replies:      [][]string{{""}},
+			minAcks:      1,
+			retries:      2,
+			wantAccepted: []bool{true},
+			wantAttempts: []int{1},
+			wantRetry:    []bool{false},
+		},
+		{
+			name:         "duplicate counts as accepted",
+			replies:      [][]string{{"duplicate: already have it"}},
+			minAcks:      1,
+			retries:      2,
+			wantAccepted: []bool{true},
+			wantAttempts: []int{1},
+			wantRetry:    []bool{false},
+		},
+		{
+			name:         "rate limited is retried",
+			replies:      [][]string{{"rate-limited: slow down", ""}},
+			minAcks:      1,
+			retries:      2,
+			wantAccepted: []bool{true},
+			wantAttempts: []int{2},
+			wantRetry:    []bool{false},
+			minLatency:   20 * time.Millisecond,
+		},
+		{
+			name:         "error is retried with backoff until retries run out",
+			replies:      [][]string{{"error: database locked"}},
+			minAcks:      1,
+			retries:      2,
+			wantQuorum:   true,
+			wantAccepted: []bool{false},
+			wantAttempts: []int{3},
+			wantRetry:    []bool{true},
+			minLatency:   60 * time.Millisecond,
+		},
+		{
+			name:         "terminal rejection is not retried",
+			replies:      [][]string{{"blocked: not on the allow list"}},
+			minAcks:      1,
+			retries:      2,
+			wantQuorum:   true,
+			wantAccepted: []bool{false},
+			wantAttempts: []int{1},
+			wantRetry:    []bool{false},
+		},
+		{
+			name:         "dropped connection is not an acceptance",
+			replies:      [][]string{{dropReply}},
+			minAcks:      1,
+			retries:      0,
+			wantQuorum:   true,
+			wantAccepted: []bool{false},
+			wantAttempts: []int{1},
+			wantRetry:    []bool{true},
+		},
+		{
+			name:         "dropped connection is retried",
+			replies:      [][]string{{dropReply, ""}},
+			minAcks:      1,
+			retries:      1,
+			wantAccepted: []bool{true},
+			wantAttempts: []int{2},
+			wantRetry:    []bool{false},
+		},
+		{
+			name:         "best effort never fails",
+			replies:      [][]string{{"invalid: bad tags"}, {"pow: difficulty 30"}},
+			minAcks:      0,
+			retries:      2,
+			wantAccepted: []bool{false, false},
+			wantAttempts: []int{1, 1},
+			wantRetry:    []bool{false, false},
+		},
+		{
+			name:         "quorum not met",
+			replies:      [][]string{{""}, {"restricted: members only"}},
+			minAcks:      2,
+			retries:      0,
+			wantQuorum:   true,
+			wantAccepted: []bool{true, false},
+			wantAttempts: []int{1, 1},
+			wantRetry:    []bool{false, false},
+		},
+		{
+			name:         "quorum met",
+			replies:      [][]string{{""}, {"duplicate: seen"}, {"blocked: no"}},
+			minAcks:      2,
+			retries:      0,
+			wantAccepted: []bool{true, true, false},
+			wantAttempts: []int{1, 1, 1},
+			wantRetry:    []bool{false, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var relays []*authRelay
+			var urls []string
+			for _, replies := range tt.replies {
+				stand, url := newScriptedRelay(t, replies...)
+				relays = append(relays, stand)
+				urls = append(urls, url)
+			}
+
+			policy := DefaultPolicy()
+			policy.MinAcks = tt.minAcks
+			policy.Retries = tt.retries
+			policy.Backoff = 20 * time.Millisecond
+			results, err := PublishToRelays(context.Background(), urls, signedEvent(t, 1, tt.name), policy)
+			if tt.wantQuorum != errors.Is(err, ErrQuorumNotMet) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !tt.wantQuorum && err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+			if len(results) != len(urls) {
+				t.Fatalf("expected %d results, got %+v", len(urls), results)
+			}
+			for i, result := range results {
+				if result.Relay != urls[i] || result.Accepted != tt.wantAccepted[i] || result.Attempts != tt.wantAttempts[i] || result.Retryable != tt.wantRetry[i] {
+					t.Fatalf("unexpected result %d: %+v", i, result)
+				}
+				if result.Accepted != (result.Err == nil) {
+					t.Fatalf("result %d: accepted %v with error %v", i, result.Accepted, result.Err)
+				}
+				if result.Latency < tt.minLatency {
+					t.Fatalf("result %d: expected backoff of at least %s, took %s", i, tt.minLatency, result.Latency)
+				}
+				relays[i].mu.Lock()
+				attempts := relays[i].attempts
+				relays[i].mu.Unlock()
+				if attempts != result.Attempts {
+					t.Fatalf("result %d: relay saw %d attempt(s), result reports %d", i, attempts, result.Attempts)
+				}
+			}
+		})
+	}
+}
+
+func TestPublishToRelaysReportsQuorum(t *testing.T) {
+	_, accepting := newScriptedRelay(t, "")
+	_, rejecting := newScriptedRelay(t, "blocked: no")
+
+	policy := DefaultPolicy()
+	policy.MinAcks = 2
+	// the same relay listed twice is published to, and counted, once
+	_, err := PublishToRelays(context.Background(), []string{accepting, accepting + "/", rejecting}, signedEvent(t, 1, "quorum"), policy)
+	if !errors.Is(err, ErrQuorumNotMet) || !strings.Contains(err.Error(), "1 of 2") {
+		t.Fatalf("expected 1 of 2 acceptances, got %v", err)
+	}
+}
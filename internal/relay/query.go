@@ -0,0 +1,141 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+func QueryRelay(ctx context.Context, url string, filter nostrlib.Filter, auth *Auth) ([]*nostrlib.Event, error) {
+	connectCtx, cancel := context.WithTimeout(ctx, DefaultConnectTimeout)
+	conn, err := nostrlib.RelayConnect(connectCtx, url)
+	cancel()
+	if err != nil {
+		return nil, fmt.Errorf("connecting: %w", err)
+	}
+	defer conn.Close()
+
+	queryCtx, cancel := context.WithTimeout(ctx, DefaultOKTimeout)
+	defer cancel()
+
+	events, reason, err := querySubscription(queryCtx, conn, filter)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		return events, nil
+	}
+	if !authRequired(reason) || !auth.enabled(url) {
+		return events, fmt.Errorf("subscription closed: %s", reason)
+	}
+
+	if err := auth.authenticate(queryCtx, conn); err != nil {
+		return nil, err
+	}
+	events, reason, err = querySubscription(queryCtx, conn, filter)
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		return events, fmt.Errorf("subscription closed: %s", reason)
+	}
+	return events, nil
+}
+
+// querySubscription collects stored events until EOSE. Unlike QuerySync it
+// also stops on CLOSED so callers can react to auth-required: replies.
+func querySubscription(ctx context.Context, conn *nostrlib.Relay, filter nostrlib.Filter) ([]*nostrlib.Event, string, error) {
+	sub, err := conn.Subscribe(ctx, nostrlib.Filters{filter})
+	if err != nil {
+		return nil, "", err
+	}
+	defer sub.Unsub()
+
+	var events []*nostrlib.Event
+	for {
+		select {
+		case ev, ok := <-sub.Events:
+			if !ok {
+				return events, "", nil
+			}
+			events = append(events, ev)
+		case <-sub.EndOfStoredEvents:
+			return events, "", nil
+		case reason := <-sub.ClosedReason:
+			return events, reason, nil
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return events, "", nil
+			}
+			return events, "", ctx.Err()
+		}
+	}
+}
+
+// QueryRelays runs filter against every relay in parallel and merges the
+// answers, keeping one copy of each event ID. It only fails when no relay
+// could be queried at all.
+func QueryRelays(ctx context.Context, relays []string, filter nostrlib.Filter, auth *Auth) ([]*nostrlib.Event, error) {
+	targets := uniqueRelays(relays)
+	if len(targets) == 0 {
+		return nil, errors.New("no relays to query")
+	}
+
+	type answer struct {
+		events []*nostrlib.Event
+		err    error
+	}
+	answers := make([]answer, len(targets))
+
+	var wg sync.WaitGroup
+	for i, url := range targets {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			events, err := QueryRelay(ctx, url, filter, auth)
+			answers[i] = answer{events: events, err: err}
+		}(i, url)
+	}
+	wg.Wait()
+
+	seen := make(map[string]struct{})
+	var merged []*nostrlib.Event
+	var errs []error
+	for i, answer := range answers {
+		if answer.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", targets[i], answer.err))
+		}
+		for _, ev := range answer.events {
+			if _, ok := seen[ev.ID]; ok {
+				continue
+			}
+			seen[ev.ID] = struct{}{}
+			merged = append(merged, ev)
+		}
+	}
+	if len(errs) == len(targets) {
+		return nil, errors.Join(errs...)
+	}
+	return merged, nil
+}
+
+// FetchEvent returns the event with the given id from the first relay that
+// has a correctly signed copy.
+func FetchEvent(ctx context.Context, relays []string, id string, auth *Auth) (*nostrlib.Event, error) {
+	events, err := QueryRelays(ctx, relays, nostrlib.Filter{IDs: []string{id}}, auth)
+	if err != nil {
+		return nil, err
+	}
+	for _, ev := range events {
+		if ev.ID != id {
+			continue
+		}
+		if ok, _ := ev.CheckSignature(); ok {
+			return ev, nil
+		}
+	}
+	return nil, ErrNotFound
+}
@@ -0,0 +1,82 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+var ErrNotFound = errors.New("event not found on the queried relays")
+
+func IsReplaceable(kind int) bool {
+	return kind == 0 || kind == 3 || (kind >= 10000 && kind < 20000)
+}
+
+func IsAddressable(kind int) bool {
+	return kind >= 30000 && kind < 40000
+}
+
+// ReplaceableKey identifies the slot an event occupies under NIP-01: kind and
+// pubkey for replaceable kinds, plus the d tag for addressable ones. Regular
+// events are keyed by their ID.
+func ReplaceableKey(ev *nostrlib.Event) string {
+	switch {
+	case IsReplaceable(ev.Kind):
+		return fmt.Sprintf("%d:%s", ev.Kind, ev.PubKey)
+	case IsAddressable(ev.Kind):
+		return fmt.Sprintf("%d:%s:%s", ev.Kind, ev.PubKey, ev.Tags.GetD())
+	}
+	return ev.ID
+}
+
+// Newest keeps only the winning version of every replaceable or addressable
+// event: the highest created_at, with the lowest ID breaking ties.
+func Newest(events []*nostrlib.Event) []*nostrlib.Event {
+	winners := make(map[string]int)
+	var kept []*nostrlib.Event
+	for _, ev := range events {
+		key := ReplaceableKey(ev)
+		index, ok := winners[key]
+		if !ok {
+			winners[key] = len(kept)
+			kept = append(kept, ev)
+			continue
+		}
+		if supersedes(ev, kept[index]) {
+			kept[index] = ev
+		}
+	}
+	return kept
+}
+
+func supersedes(candidate, current *nostrlib.Event) bool {
+	if candidate.CreatedAt != current.CreatedAt {
+		return candidate.CreatedAt > current.CreatedAt
+	}
+	return candidate.ID < current.ID
+}
+
+// FetchLatest queries every relay in parallel and returns the newest version
+// of the replaceable or addressable event matched by filter.
+func FetchLatest(ctx context.Context, relays []string, filter nostrlib.Filter, auth *Auth) (*nostrlib.Event, error) {
+	events, err := QueryRelays(ctx, relays, filter, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *nostrlib.Event
+	for _, ev := range Newest(events) {
+		if !IsReplaceable(ev.Kind) && !IsAddressable(ev.Kind) {
+			continue
+		}
+		if latest == nil || supersedes(ev, latest) {
+			latest = ev
+		}
+	}
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+	return latest, nil
+}
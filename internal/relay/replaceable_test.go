@@ -0,0 +1,40 @@
+package relay
+
+import (
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+func TestNewest(t *testing.T) {
+	const alice = "a1"
+	const bob = "b2"
+	events := []*nostrlib.Event{
+		{ID: "01", PubKey: alice, Kind: 0, CreatedAt: 100},
+		{ID: "02", PubKey: alice, Kind: 0, CreatedAt: 200},
+		{ID: "03", PubKey: bob, Kind: 0, CreatedAt: 50},
+		{ID: "0f", PubKey: alice, Kind: 10002, CreatedAt: 300},
+		{ID: "0a", PubKey: alice, Kind: 10002, CreatedAt: 300},
+		{ID: "04", PubKey: alice, Kind: 30023, CreatedAt: 10, Tags: nostrlib.Tags{{"d", "one"}}},
+		{ID: "05", PubKey: alice, Kind: 30023, CreatedAt: 5, Tags: nostrlib.Tags{{"d", "two"}}},
+		{ID: "06", PubKey: alice, Kind: 30023, CreatedAt: 20, Tags: nostrlib.Tags{{"d", "one"}}},
+		{ID: "07", PubKey: alice, Kind: 1, CreatedAt: 1},
+		{ID: "08", PubKey: alice, Kind: 1, CreatedAt: 2},
+	}
+
+	kept := Newest(events)
+	var ids []string
+	for _, ev := range kept {
+		ids = append(ids, ev.ID)
+	}
+
+	expected := []string{"02", "03", "0a", "06", "05", "07", "08"}
+	if len(ids) != len(expected) {
+		t.Fatalf("expected %v got %v", expected, ids)
+	}
+	for i := range expected {
+		if ids[i] != expected[i] {
+			t.Fatalf("expected %v got %v", expected, ids)
+		}
+	}
+}
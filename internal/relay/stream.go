@@ -0,0 +1,173 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+const (
+	streamMinBackoff = time.Second
+	streamMaxBackoff = time.Minute
+	// streamSeenLimit is how many recent event IDs are remembered to drop
+	// copies sent by other relays or replayed after a reconnect.
+	streamSeenLimit = 10000
+)
+
+type StreamHandlers struct {
+	OnEvent func(relayURL string, ev *nostrlib.Event)
+	OnError func(relayURL string, err error)
+}
+
+// Stream keeps a subscription open on every relay until ctx is done,
+// reconnecting with exponential backoff. Each event ID is delivered once no
+// matter how many relays send it, as long as it is among the last
+// streamSeenLimit IDs seen.
+func Stream(ctx context.Context, relays []string, filter nostrlib.Filter, auth *Auth, handlers StreamHandlers) error {
+	targets := uniqueRelays(relays)
+	if len(targets) == 0 {
+		return errors.New("no relays to stream from")
+	}
+
+	var mu sync.Mutex
+	seen := newSeenIDs(streamSeenLimit)
+	deliver := func(url string, ev *nostrlib.Event) {
+		mu.Lock()
+		fresh := seen.add(ev.ID)
+		mu.Unlock()
+		if fresh && handlers.OnEvent != nil {
+			handlers.OnEvent(url, ev)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, url := range targets {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			streamRelay(ctx, url, filter, auth, deliver, handlers.OnError)
+		}(url)
+	}
+	wg.Wait()
+	return nil
+}
+
+// seenIDs remembers the most recent event IDs in a fixed-size ring, so a
+// long-running stream does not grow without bound.
+type seenIDs struct {
+	ids  map[string]struct{}
+	ring []string
+	next int
+}
+
+func newSeenIDs(size int) *seenIDs {
+	return &seenIDs{ids: make(map[string]struct{}, size), ring: make([]string, size)}
+}
+
+// add records id and reports whether it was new. The oldest ID is forgotten
+// once the ring is full.
+func (s *seenIDs) add(id string) bool {
+	if _, ok := s.ids[id]; ok {
+		return false
+	}
+	if old := s.ring[s.next]; old != "" {
+		delete(s.ids, old)
+	}
+	s.ring[s.next] = id
+	s.ids[id] = struct{}{}
+	s.next = (s.next + 1) % len(s.ring)
+	return true
+}
+
+func streamRelay(ctx context.Context, url string, filter nostrlib.Filter, auth *Auth, deliver func(string, *nostrlib.Event), onError func(string, error)) {
+	backoff := streamMinBackoff
+	for ctx.Err() == nil {
+		connected, err := streamOnce(ctx, url, &filter, auth, deliver)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = streamMinBackoff
+		}
+		if err == nil {
+			err = errors.New("connection closed")
+		}
+		if onError != nil {
+			onError(url, fmt.Errorf("%w; reconnecting in %s", err, backoff))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > streamMaxBackoff {
+			backoff = streamMaxBackoff
+		}
+	}
+}
+
+// streamOnce runs one connection. It advances filter.Since as events arrive
+// so a reconnect does not replay the whole history.
+func streamOnce(ctx context.Context, url string, filter *nostrlib.Filter, auth *Auth, deliver func(string, *nostrlib.Event)) (bool, error) {
+	connectCtx, cancel := context.WithTimeout(ctx, DefaultConnectTimeout)
+	conn, err := nostrlib.RelayConnect(connectCtx, url)
+	cancel()
+	if err != nil {
+		return false, fmt.Errorf("connecting: %w", err)
+	}
+	defer conn.Close()
+
+	authenticated := false
+	for {
+		sub, err := conn.Subscribe(ctx, nostrlib.Filters{*filter})
+		if err != nil {
+			return true, err
+		}
+
+		reason, err := readSubscription(ctx, conn, sub, filter, url, deliver)
+		sub.Unsub()
+		if err != nil || reason == "" {
+			return true, err
+		}
+		if authenticated || !authRequired(reason) || !auth.enabled(url) {
+			return true, fmt.Errorf("subscription closed: %s", reason)
+		}
+		if err := auth.authenticate(ctx, conn); err != nil {
+			return true, err
+		}
+		authenticated = true
+	}
+}
+
+func readSubscription(ctx context.Context, conn *nostrlib.Relay, sub *nostrlib.Subscription, filter *nostrlib.Filter, url string, deliver func(string, *nostrlib.Event)) (string, error) {
+	for {
+		select {
+		case ev, ok := <-sub.Events:
+			if !ok {
+				return "", conn.ConnectionError
+			}
+			// a future-dated event must not make reconnects skip what is
+			// published until then
+			since := ev.CreatedAt
+			if now := nostrlib.Now(); since > now {
+				since = now
+			}
+			if filter.Since == nil || since > *filter.Since {
+				filter.Since = &since
+			}
+			deliver(url, ev)
+		case reason := <-sub.ClosedReason:
+			return reason, nil
+		case <-conn.Context().Done():
+			return "", conn.ConnectionError
+		case <-ctx.Done():
+			return "", nil
+		}
+	}
+}
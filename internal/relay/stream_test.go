@@ -0,0 +1,78 @@
+package relay
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+func TestSeenIDsForgetsOldest(t *testing.T) {
+	seen := newSeenIDs(2)
+	if !seen.add("a") || !seen.add("b") {
+		t.Fatalf("expected new ids to be added")
+	}
+	if seen.add("a") {
+		t.Fatalf("expected a to be a duplicate")
+	}
+	if !seen.add("c") {
+		t.Fatalf("expected c to be added")
+	}
+	if !seen.add("a") {
+		t.Fatalf("expected a to be forgotten once the ring was full")
+	}
+	if len(seen.ids) != 2 {
+		t.Fatalf("expected two remembered ids, got %v", seen.ids)
+	}
+}
+
+func TestStreamDeliversEachEventOnce(t *testing.T) {
+	first, firstURL := newAuthRelay(t)
+	second, secondURL := newAuthRelay(t)
+	_, newAuth := testAuth(t)
+	shared := []nostrlib.Event{signedEvent(t, 1, "one"), signedEvent(t, 1, "two")}
+	extra := signedEvent(t, 1, "three")
+	first.events = append(first.events, shared...)
+	second.events = append(append(second.events, shared...), extra)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var mu sync.Mutex
+	delivered := make(map[string]int)
+	done := make(chan struct{})
+	handlers := StreamHandlers{OnEvent: func(_ string, ev *nostrlib.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		delivered[ev.ID]++
+		if len(delivered) == 3 {
+			close(done)
+		}
+	}}
+	go func() {
+		select {
+		case <-done:
+			// give the slower relay time to send its copies
+			time.Sleep(200 * time.Millisecond)
+		case <-ctx.Done():
+		}
+		cancel()
+	}()
+
+	filter := nostrlib.Filter{Kinds: []int{1}}
+	if err := Stream(ctx, []string{firstURL, secondURL}, filter, newAuth(firstURL, secondURL), handlers); err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(delivered) != 3 {
+		t.Fatalf("expected three events, got %v", delivered)
+	}
+	for id, count := range delivered {
+		if count != 1 {
+			t.Fatalf("event %s delivered %d times", id, count)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package nip00
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProfileMetadata models the NIP-01 and NIP-24 kind 0 fields. Keys written by
+// other clients that are not modelled here are kept in Extra so republishing
+// never drops them.
+type ProfileMetadata struct {
+	Name        string    `json:"name,omitempty"`
+	DisplayName string    `json:"display_name,omitempty"`
+	About       string    `json:"about,omitempty"`
+	Picture     string    `json:"picture,omitempty"`
+	Banner      string    `json:"banner,omitempty"`
+	Website     string    `json:"website,omitempty"`
+	NIP05       string    `json:"nip05,omitempty"`
+	LUD16       string    `json:"lud16,omitempty"`
+	LUD06       string    `json:"lud06,omitempty"`
+	Bot         bool      `json:"bot,omitempty"`
+	Birthday    *Birthday `json:"birthday,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type Birthday struct {
+	Year  int `json:"year,omitempty"`
+	Month int `json:"month,omitempty"`
+	Day   int `json:"day,omitempty"`
+}
+
+func (p *ProfileMetadata) fieldPointer(key string) any {
+	switch key {
+	case "name":
+		return &p.Name
+	case "display_name":
+		return &p.DisplayName
+	case "about":
+		return &p.About
+	case "picture":
+		return &p.Picture
+	case "banner":
+		return &p.Banner
+	case "website":
+		return &p.Website
+	case "nip05":
+		return &p.NIP05
+	case "lud16":
+		return &p.LUD16
+	case "lud06":
+		return &p.LUD06
+	case "bot":
+		return &p.Bot
+	case "birthday":
+		return &p.Birthday
+	}
+	return nil
+}
+
+func (p *ProfileMetadata) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*p = ProfileMetadata{}
+	for key, value := range raw {
+		if target := p.fieldPointer(key); target != nil {
+			// a malformed value written by another client is kept verbatim
+			if err := json.Unmarshal(value, target); err == nil {
+				continue
+			}
+		}
+		if p.Extra == nil {
+			p.Extra = make(map[string]json.RawMessage)
+		}
+		p.Extra[key] = value
+	}
+	return nil
+}
+
+func (p ProfileMetadata) MarshalJSON() ([]byte, error) {
+	type plain ProfileMetadata
+	known, err := json.Marshal(plain(p))
+	if err != nil {
+		return nil, err
+	}
+	merged := make(map[string]json.RawMessage)
+	for key, value := range p.Extra {
+		merged[key] = value
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(known, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return json.Marshal(merged)
+}
+
+// Set assigns a field from its command-line form. Booleans accept
+// true/false and birthdays YYYY-MM-DD or MM-DD.
+func (p *ProfileMetadata) Set(key, value string) error {
+	key = strings.TrimSpace(key)
+	value = strings.TrimSpace(value)
+	switch key {
+	case "bot":
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bot value %q: use true or false", value)
+		}
+		p.Bot = parsed
+	case "birthday":
+		birthday, err := parseBirthday(value)
+		if err != nil {
+			return err
+		}
+		p.Birthday = birthday
+	default:
+		target, ok := p.fieldPointer(key).(*string)
+		if !ok {
+			return fmt.Errorf("unknown profile field %q", key)
+		}
+		*target = value
+	}
+	delete(p.Extra, key)
+	return nil
+}
+
+// Unset removes a modelled field or any extra key.
+func (p *ProfileMetadata) Unset(key string) error {
+	key = strings.TrimSpace(key)
+	_, extra := p.Extra[key]
+	target := p.fieldPointer(key)
+	if target == nil && !extra {
+		return fmt.Errorf("unknown profile field %q", key)
+	}
+	switch field := target.(type) {
+	case *string:
+		*field = ""
+	case *bool:
+		*field = false
+	case **Birthday:
+		*field = nil
+	}
+	delete(p.Extra, key)
+	return nil
+}
+
+func parseBirthday(value string) (*Birthday, error) {
+	parts := strings.Split(value, "-")
+	numbers := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid birthday %q: use YYYY-MM-DD or MM-DD", value)
+		}
+		numbers[i] = n
+	}
+	var birthday Birthday
+	switch len(numbers) {
+	case 3:
+		birthday = Birthday{Year: numbers[0], Month: numbers[1], Day: numbers[2]}
+	case 2:
+		birthday = Birthday{Month: numbers[0], Day: numbers[1]}
+	default:
+		return nil, fmt.Errorf("invalid birthday %q: use YYYY-MM-DD or MM-DD", value)
+	}
+	if birthday.Month < 1 || birthday.Month > 12 || birthday.Day < 1 || birthday.Day > 31 {
+		return nil, fmt.Errorf("invalid birthday %q", value)
+	}
+	return &birthday, nil
+}
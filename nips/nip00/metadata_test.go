@@ -0,0 +1,56 @@
+package nip00
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProfileMetadataKeepsUnknownKeys(t *testing.T) {
+	input := `{"name":"alice","about":"hi","lud16":"alice@getalby.com","bot":"yes","pronouns":"she/her","nested":{"a":1}}`
+
+	var metadata ProfileMetadata
+	if err := json.Unmarshal([]byte(input), &metadata); err != nil {
+		t.Fatal(err)
+	}
+	if metadata.Name != "alice" || metadata.LUD16 != "alice@getalby.com" {
+		t.Fatalf("unexpected known fields: %+v", metadata)
+	}
+
+	if err := metadata.Set("display_name", "Alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := metadata.Unset("about"); err != nil {
+		t.Fatal(err)
+	}
+	if err := metadata.Set("birthday", "05-17"); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := json.Marshal(metadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"birthday":{"month":5,"day":17},"bot":"yes","display_name":"Alice","lud16":"alice@getalby.com","name":"alice","nested":{"a":1},"pronouns":"she/her"}`
+	if string(output) != expected {
+		t.Fatalf("expected %s got %s", expected, output)
+	}
+}
+
+func TestProfileMetadataSetAndUnset(t *testing.T) {
+	var metadata ProfileMetadata
+	if err := metadata.Set("bot", "maybe"); err == nil {
+		t.Fatal("expected an invalid bot value to fail")
+	}
+	if err := metadata.Set("favorite_color", "blue"); err == nil {
+		t.Fatal("expected an unknown field to fail")
+	}
+	if err := metadata.Set("bot", "true"); err != nil || !metadata.Bot {
+		t.Fatalf("expected bot to be set, got %v (%v)", metadata.Bot, err)
+	}
+	if err := metadata.Unset("bot"); err != nil || metadata.Bot {
+		t.Fatalf("expected bot to be cleared, got %v (%v)", metadata.Bot, err)
+	}
+	if err := metadata.Unset("favorite_color"); err == nil {
+		t.Fatal("expected unsetting an absent unknown field to fail")
+	}
+}
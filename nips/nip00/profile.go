@@ -4,64 +4,61 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	nostrlib "github.com/nbd-wtf/go-nostr"
 
 	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip01"
 	nostrkeys "nostr-cli/nostr"
 )
 
-type ProfileMetadata struct {
-	Name    string `json:"name,omitempty"`
-	About   string `json:"about,omitempty"`
-	Picture string `json:"picture,omitempty"`
-}
+var ErrProfileNotFound = errors.New("profile not found on configured relays")
 
-func PublishProfile(ctx context.Context, activeProfile *nostrkeys.Profile, sk string, profile ProfileMetadata) error {
+// PublishProfile publishes profile as a new kind 0 event replacing previous,
+// the current one (nil if there is none).
+func PublishProfile(ctx context.Context, activeProfile *nostrkeys.Profile, sk string, previous *nostrlib.Event, profile ProfileMetadata, policy relay.Policy) ([]relay.PublishResult, error) {
 	content, err := json.Marshal(profile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	ev := nostrlib.Event{
-		PubKey:    activeProfile.PublicKey,
-		CreatedAt: nostrlib.Now(),
+	ev := &nostrlib.Event{
+		CreatedAt: nip01.After(previous),
 		Kind:      0,
 		Content:   string(content),
 	}
+	return nip01.PublishEvent(ctx, activeProfile, sk, ev, nil, policy)
+}
 
-	if err := ev.Sign(sk); err != nil {
-		return err
+func FetchProfile(ctx context.Context, relays []string, pubKey string, auth *relay.Auth) (*ProfileMetadata, error) {
+	ev, err := FetchProfileEvent(ctx, relays, pubKey, auth)
+	if err != nil {
+		return nil, err
 	}
-
-	relay.PublishToRelays(ctx, activeProfile.Relays, ev)
-	return nil
+	return ParseProfile(ev)
 }
 
-func FetchProfile(ctx context.Context, relays []string, pubKey string) (*ProfileMetadata, error) {
+// FetchProfileEvent returns the latest kind 0 event of pubKey.
+func FetchProfileEvent(ctx context.Context, relays []string, pubKey string, auth *relay.Auth) (*nostrlib.Event, error) {
 	if pubKey == "" {
 		return nil, errors.New("a public key is required")
 	}
 
-	for _, url := range relays {
-		relay, err := nostrlib.RelayConnect(ctx, url)
-		if err != nil {
-			continue
-		}
-
-		events, err := relay.QuerySync(ctx, nostrlib.Filter{Kinds: []int{0}, Authors: []string{pubKey}, Limit: 1})
-		relay.Close()
-		if err != nil || len(events) == 0 {
-			continue
-		}
-
-		var profile ProfileMetadata
-		if err := json.Unmarshal([]byte(events[0].Content), &profile); err != nil {
-			continue
+	ev, err := relay.FetchLatest(ctx, relays, nostrlib.Filter{Kinds: []int{0}, Authors: []string{pubKey}}, auth)
+	if err != nil {
+		if errors.Is(err, relay.ErrNotFound) {
+			return nil, ErrProfileNotFound
 		}
-
-		return &profile, nil
+		return nil, err
 	}
+	return ev, nil
+}
 
-	return nil, errors.New("profile not found on configured relays")
+func ParseProfile(ev *nostrlib.Event) (*ProfileMetadata, error) {
+	var profile ProfileMetadata
+	if err := json.Unmarshal([]byte(ev.Content), &profile); err != nil {
+		return nil, fmt.Errorf("parsing profile metadata: %w", err)
+	}
+	return &profile, nil
 }
@@ -0,0 +1,65 @@
+package nip00
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	nip05Pattern = regexp.MustCompile(`^[a-z0-9._-]+@[a-z0-9-]+(\.[a-z0-9-]+)+$`)
+	lud16Pattern = regexp.MustCompile(`^[a-z0-9._+-]+@[a-z0-9-]+(\.[a-z0-9-]+)+$`)
+)
+
+func (p ProfileMetadata) Validate() error {
+	var errs []error
+	for _, field := range []struct {
+		key, value string
+		bareHost   bool
+	}{
+		{"picture", p.Picture, false},
+		{"banner", p.Banner, false},
+		{"website", p.Website, true},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if err := validateURL(field.value, field.bareHost); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", field.key, err))
+		}
+	}
+	if p.NIP05 != "" && !nip05Pattern.MatchString(strings.ToLower(p.NIP05)) {
+		errs = append(errs, fmt.Errorf("nip05: %q is not an address like name@example.com", p.NIP05))
+	}
+	if p.LUD16 != "" && !lud16Pattern.MatchString(strings.ToLower(p.LUD16)) {
+		errs = append(errs, fmt.Errorf("lud16: %q is not a lightning address like name@wallet.com", p.LUD16))
+	}
+	if p.LUD06 != "" && !strings.HasPrefix(strings.ToLower(p.LUD06), "lnurl1") {
+		errs = append(errs, fmt.Errorf("lud06: %q is not an LNURL (lnurl1...)", p.LUD06))
+	}
+	if p.Birthday != nil && (p.Birthday.Month < 0 || p.Birthday.Month > 12 || p.Birthday.Day < 0 || p.Birthday.Day > 31) {
+		errs = append(errs, errors.New("birthday: month or day is out of range"))
+	}
+	return errors.Join(errs...)
+}
+
+// validateURL requires an http(s) URL; bareHost also allows "example.com".
+func validateURL(value string, bareHost bool) error {
+	candidate := value
+	if bareHost && !strings.Contains(candidate, "://") {
+		candidate = "https://" + candidate
+	}
+	parsed, err := url.Parse(candidate)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid URL", value)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("%q must start with http:// or https://", value)
+	}
+	if parsed.Host == "" || !strings.Contains(parsed.Hostname(), ".") {
+		return fmt.Errorf("%q has no valid host", value)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package nip00
+
+import "testing"
+
+func TestProfileMetadataValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile ProfileMetadata
+		valid   bool
+	}{
+		{"empty", ProfileMetadata{}, true},
+		{"complete", ProfileMetadata{Picture: "https://example.com/a.png", Website: "example.com", NIP05: "_@example.com", LUD16: "alice@getalby.com", LUD06: "LNURL1DP68GURN8GHJ7"}, true},
+		{"picture without scheme", ProfileMetadata{Picture: "example.com/a.png"}, false},
+		{"website with other scheme", ProfileMetadata{Website: "ftp://example.com"}, false},
+		{"nip05 without domain", ProfileMetadata{NIP05: "alice"}, false},
+		{"lud16 url", ProfileMetadata{LUD16: "https://getalby.com/alice"}, false},
+		{"lud06 address", ProfileMetadata{LUD06: "alice@getalby.com"}, false},
+		{"birthday month", ProfileMetadata{Birthday: &Birthday{Month: 13}}, false},
+	}
+
+	for _, test := range tests {
+		err := test.profile.Validate()
+		if (err == nil) != test.valid {
+			t.Fatalf("%s: expected valid=%v, got %v", test.name, test.valid, err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package nip01
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	"nostr-cli/internal/queue"
+	"nostr-cli/internal/relay"
+	nostrkeys "nostr-cli/nostr"
+)
+
+func SignTemplate(profile *nostrkeys.Profile, sk string, ev *nostrlib.Event) error {
+	templatePubKey := strings.ToLower(strings.TrimSpace(ev.PubKey))
+	if templatePubKey != "" && templatePubKey != profile.PublicKey {
+		return fmt.Errorf("template pubkey %s does not match the active profile", templatePubKey)
+	}
+	ev.PubKey = profile.PublicKey
+	if ev.CreatedAt == 0 {
+		ev.CreatedAt = nostrlib.Now()
+	}
+	if ev.Tags == nil {
+		ev.Tags = nostrlib.Tags{}
+	}
+	return ev.Sign(sk)
+}
+
+// After returns the created_at for a new version of a replaceable event: now,
+// or one second past previous (nil if there is none) so the new version wins
+// even when both are published within the same second.
+func After(previous *nostrlib.Event) nostrlib.Timestamp {
+	now := nostrlib.Now()
+	if previous != nil && now <= previous.CreatedAt {
+		return previous.CreatedAt + 1
+	}
+	return now
+}
+
+// PublishEvent signs ev and publishes it with PublishSigned.
+func PublishEvent(ctx context.Context, profile *nostrkeys.Profile, sk string, ev *nostrlib.Event, extra []string, policy relay.Policy) ([]relay.PublishResult, error) {
+	if err := SignTemplate(profile, sk, ev); err != nil {
+		return nil, err
+	}
+	return PublishSigned(ctx, profile, ev, extra, policy)
+}
+
+// PublishSigned sends an already-signed ev to the profile's write relays plus
+// extra ones and the inboxes of the users a regular event tags. Notes and
+// articles are kept in the outbox queue for relays that could not be reached.
+func PublishSigned(ctx context.Context, profile *nostrkeys.Profile, ev *nostrlib.Event, extra []string, policy relay.Policy) ([]relay.PublishResult, error) {
+	relays := append(append([]string{}, profile.WriteRelays()...), extra...)
+	if policy.Inboxes != nil {
+		if tagged := TaggedPubKeys(ev); len(tagged) > 0 {
+			relays = append(relays, policy.Inboxes(ctx, tagged)...)
+		}
+	}
+	results, err := relay.PublishToRelays(ctx, relays, *ev, policy)
+	if _, queueErr := queue.Add(*ev, results); queueErr != nil {
+		return results, errors.Join(err, fmt.Errorf("saving to outbox queue: %w", queueErr))
+	}
+	return results, err
+}
+
+// TaggedPubKeys lists the users a reply, mention, reaction or repost is
+// addressed to. Replaceable and addressable events such as follow lists tag
+// people without notifying them, so they yield none.
+func TaggedPubKeys(ev *nostrlib.Event) []string {
+	if relay.IsReplaceable(ev.Kind) || relay.IsAddressable(ev.Kind) {
+		return nil
+	}
+	var pubKeys []string
+	for _, tag := range ev.Tags {
+		if len(tag) < 2 || tag[0] != "p" || tag[1] == ev.PubKey || !nostrlib.IsValidPublicKeyHex(tag[1]) {
+			continue
+		}
+		if !containsPubKey(pubKeys, tag[1]) {
+			pubKeys = append(pubKeys, tag[1])
+		}
+	}
+	return pubKeys
+}
+
+func containsPubKey(pubKeys []string, pubKey string) bool {
+	for _, item := range pubKeys {
+		if item == pubKey {
+			return true
+		}
+	}
+	return false
+}
+
+func VerifyEvent(ev *nostrlib.Event) error {
+	if !nostrlib.IsValidPublicKeyHex(ev.PubKey) {
+		return errors.New("invalid pubkey")
+	}
+	if expected := ev.GetID(); ev.ID != expected {
+		return fmt.Errorf("id mismatch: expected %s", expected)
+	}
+	ok, err := ev.CheckSignature()
+	if err != nil {
+		return fmt.Errorf("checking signature: %w", err)
+	}
+	if !ok {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
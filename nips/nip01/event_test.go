@@ -0,0 +1,21 @@
+package nip01
+
+import (
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+func TestAfter(t *testing.T) {
+	now := nostrlib.Now()
+	if got := After(nil); got < now {
+		t.Fatalf("expected at least %d without a previous version, got %d", now, got)
+	}
+	if got := After(&nostrlib.Event{CreatedAt: now - 60}); got < now {
+		t.Fatalf("expected the current time after an old version, got %d", got)
+	}
+	future := &nostrlib.Event{CreatedAt: now + 60}
+	if got := After(future); got != future.CreatedAt+1 {
+		t.Fatalf("expected %d after a version from the future, got %d", future.CreatedAt+1, got)
+	}
+}
@@ -6,21 +6,41 @@ import (
 	nostrlib "github.com/nbd-wtf/go-nostr"
 
 	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip27"
 	nostrkeys "nostr-cli/nostr"
 )
 
-func PublishNote(ctx context.Context, profile *nostrkeys.Profile, sk, message string) error {
-	ev := nostrlib.Event{
+// NoteOptions carries the content plus the tags and extra relays that
+// replies and quotes need on top of the profile's own relays. Mentions and
+// hashtags in Content are tagged automatically; NIP-05 handles are only
+// resolved when Resolve is set.
+type NoteOptions struct {
+	Content string
+	Tags    nostrlib.Tags
+	Relays  []string
+	Resolve nip27.Resolver
+}
+
+func PublishNote(ctx context.Context, profile *nostrkeys.Profile, sk string, opts NoteOptions, policy relay.Policy) (*nostrlib.Event, []relay.PublishResult, error) {
+	content, mentions, err := nip27.Process(opts.Content, opts.Resolve)
+	if err != nil {
+		return nil, nil, err
+	}
+	tags := opts.Tags
+	for _, tag := range mentions {
+		tags = nostrkeys.AppendUniqueTag(tags, tag)
+	}
+
+	ev := &nostrlib.Event{
 		PubKey:    profile.PublicKey,
 		CreatedAt: nostrlib.Now(),
 		Kind:      1,
-		Content:   message,
+		Content:   content,
+		Tags:      tags,
 	}
-
-	if err := ev.Sign(sk); err != nil {
-		return err
+	results, err := PublishEvent(ctx, profile, sk, ev, opts.Relays, policy)
+	if results == nil && err != nil {
+		return nil, nil, err
 	}
-
-	relay.PublishToRelays(ctx, profile.Relays, ev)
-	return nil
+	return ev, results, err
 }
@@ -0,0 +1,148 @@
+package nip02
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip01"
+	nostrkeys "nostr-cli/nostr"
+)
+
+const KindContactList = 3
+
+var ErrNotFound = errors.New("follow list not found on configured relays")
+
+type Follow struct {
+	PubKey  string `json:"pubkey"`
+	Relay   string `json:"relay,omitempty"`
+	Petname string `json:"petname,omitempty"`
+}
+
+// Tag returns the p tag for f, leaving out trailing empty fields.
+func (f Follow) Tag() nostrlib.Tag {
+	tag := nostrlib.Tag{"p", f.PubKey, f.Relay, f.Petname}
+	for len(tag) > 2 && tag[len(tag)-1] == "" {
+		tag = tag[:len(tag)-1]
+	}
+	return tag
+}
+
+// FetchContactList returns the newest Kind 3 event any of the relays has.
+func FetchContactList(ctx context.Context, relays []string, pubKey string, auth *relay.Auth) (*nostrlib.Event, error) {
+	if pubKey == "" {
+		return nil, errors.New("a public key is required")
+	}
+
+	ev, err := relay.FetchLatest(ctx, relays, nostrlib.Filter{Kinds: []int{KindContactList}, Authors: []string{pubKey}}, auth)
+	if err != nil {
+		if errors.Is(err, relay.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return ev, nil
+}
+
+func FetchFollows(ctx context.Context, relays []string, pubKey string, auth *relay.Auth) ([]Follow, error) {
+	ev, err := FetchContactList(ctx, relays, pubKey, auth)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFollows(ev.Tags), nil
+}
+
+func ParseFollows(tags nostrlib.Tags) []Follow {
+	seen := make(map[string]struct{})
+	var follows []Follow
+	for _, tag := range tags {
+		pubKey, ok := followedKey(tag)
+		if !ok {
+			continue
+		}
+		if _, ok := seen[pubKey]; ok {
+			continue
+		}
+		seen[pubKey] = struct{}{}
+		follow := Follow{PubKey: pubKey}
+		if len(tag) > 2 {
+			follow.Relay = strings.TrimSpace(tag[2])
+		}
+		if len(tag) > 3 {
+			follow.Petname = strings.TrimSpace(tag[3])
+		}
+		follows = append(follows, follow)
+	}
+	return follows
+}
+
+func followedKey(tag nostrlib.Tag) (string, bool) {
+	if len(tag) < 2 || tag[0] != "p" {
+		return "", false
+	}
+	pubKey := strings.ToLower(strings.TrimSpace(tag[1]))
+	return pubKey, nostrlib.IsValidPublicKeyHex(pubKey)
+}
+
+// AddFollow appends a p tag for f, or fills in the relay hint and petname of
+// an existing one when f sets them. Every other tag is kept as it was.
+func AddFollow(tags nostrlib.Tags, f Follow) (nostrlib.Tags, bool) {
+	updated := append(nostrlib.Tags{}, tags...)
+	for i, tag := range updated {
+		if pubKey, ok := followedKey(tag); !ok || pubKey != f.PubKey {
+			continue
+		}
+		merged := append(nostrlib.Tag{}, tag...)
+		set := func(i int, value string) {
+			if value == "" {
+				return
+			}
+			for len(merged) <= i {
+				merged = append(merged, "")
+			}
+			merged[i] = value
+		}
+		set(2, f.Relay)
+		set(3, f.Petname)
+		if strings.Join(merged, "\x00") == strings.Join(tag, "\x00") {
+			return tags, false
+		}
+		updated[i] = merged
+		return updated, true
+	}
+	return append(updated, f.Tag()), true
+}
+
+// RemoveFollow drops every p tag for pubKey.
+func RemoveFollow(tags nostrlib.Tags, pubKey string) (nostrlib.Tags, bool) {
+	var kept nostrlib.Tags
+	removed := false
+	for _, tag := range tags {
+		if key, ok := followedKey(tag); ok && key == pubKey {
+			removed = true
+			continue
+		}
+		kept = append(kept, tag)
+	}
+	if !removed {
+		return tags, false
+	}
+	return kept, true
+}
+
+// PublishContactList replaces previous (nil for a new list) with tags. The
+// content, which some clients use for relay settings, is kept.
+func PublishContactList(ctx context.Context, profile *nostrkeys.Profile, sk string, previous *nostrlib.Event, tags nostrlib.Tags, policy relay.Policy) (*nostrlib.Event, []relay.PublishResult, error) {
+	ev := &nostrlib.Event{Kind: KindContactList, CreatedAt: nip01.After(previous), Tags: tags}
+	if previous != nil {
+		ev.Content = previous.Content
+	}
+	results, err := nip01.PublishEvent(ctx, profile, sk, ev, nil, policy)
+	if results == nil && err != nil {
+		return nil, nil, err
+	}
+	return ev, results, err
+}
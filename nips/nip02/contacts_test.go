@@ -0,0 +1,75 @@
+package nip02
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+var (
+	alice = strings.Repeat("a", 64)
+	bob   = strings.Repeat("b", 64)
+	carol = strings.Repeat("c", 64)
+)
+
+func TestAddFollow(t *testing.T) {
+	original := nostrlib.Tags{
+		{"p", alice, "wss://alice.example", "al", "extra"},
+		{"p", "not-a-key"},
+		{"t", "nostr"},
+		{"p", bob},
+	}
+
+	cases := []struct {
+		name     string
+		follow   Follow
+		expected nostrlib.Tags
+		changed  bool
+	}{
+		{
+			name:     "new follow is appended",
+			follow:   Follow{PubKey: carol, Petname: "cc"},
+			expected: append(append(nostrlib.Tags{}, original...), nostrlib.Tag{"p", carol, "", "cc"}),
+			changed:  true,
+		},
+		{
+			name:     "existing follow keeps unknown fields",
+			follow:   Follow{PubKey: alice, Relay: "wss://new.example"},
+			expected: nostrlib.Tags{{"p", alice, "wss://new.example", "al", "extra"}, original[1], original[2], original[3]},
+			changed:  true,
+		},
+		{
+			name:     "petname is added to a bare tag",
+			follow:   Follow{PubKey: bob, Petname: "bobby"},
+			expected: nostrlib.Tags{original[0], original[1], original[2], {"p", bob, "", "bobby"}},
+			changed:  true,
+		},
+		{
+			name:     "nothing to update",
+			follow:   Follow{PubKey: bob},
+			expected: original,
+		},
+	}
+	for _, tc := range cases {
+		tags, changed := AddFollow(original, tc.follow)
+		if changed != tc.changed || !reflect.DeepEqual(tags, tc.expected) {
+			t.Fatalf("%s: got %v (changed %v), expected %v", tc.name, tags, changed, tc.expected)
+		}
+	}
+	if original[0][2] != "wss://alice.example" {
+		t.Fatalf("AddFollow modified its input: %v", original[0])
+	}
+}
+
+func TestRemoveFollow(t *testing.T) {
+	tags := nostrlib.Tags{{"p", alice}, {"t", "nostr"}, {"p", strings.ToUpper(alice)}, {"p", bob}}
+	kept, removed := RemoveFollow(tags, alice)
+	if !removed || !reflect.DeepEqual(kept, nostrlib.Tags{{"t", "nostr"}, {"p", bob}}) {
+		t.Fatalf("unexpected result %v (removed %v)", kept, removed)
+	}
+	if _, removed := RemoveFollow(tags, carol); removed {
+		t.Fatalf("expected nothing to be removed for an unfollowed key")
+	}
+}
@@ -0,0 +1,110 @@
+package nip05
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+var (
+	ErrNameNotFound = errors.New("name not listed in nostr.json")
+	ErrMismatch     = errors.New("nip05 address points to a different public key")
+)
+
+// NIP-05 forbids following redirects from the well-known endpoint.
+var httpClient = &http.Client{
+	Timeout: 10 * time.Second,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+type Result struct {
+	Name   string
+	Domain string
+	PubKey string
+	Relays []string
+}
+
+type wellKnown struct {
+	Names  map[string]string   `json:"names"`
+	Relays map[string][]string `json:"relays"`
+}
+
+// ParseIdentifier splits name@domain; a bare domain stands for _@domain.
+func ParseIdentifier(identifier string) (string, string, error) {
+	identifier = strings.ToLower(strings.TrimSpace(identifier))
+	name, domain := "_", identifier
+	if at := strings.Index(identifier, "@"); at >= 0 {
+		name, domain = identifier[:at], identifier[at+1:]
+	}
+	if name == "" || domain == "" || strings.ContainsAny(domain, "@/ ") || !strings.Contains(domain, ".") {
+		return "", "", fmt.Errorf("%q is not a nip05 address like name@example.com", identifier)
+	}
+	return name, domain, nil
+}
+
+func IsIdentifier(value string) bool {
+	if !strings.Contains(value, "@") {
+		return false
+	}
+	_, _, err := ParseIdentifier(value)
+	return err == nil
+}
+
+func Lookup(ctx context.Context, identifier string) (*Result, error) {
+	name, domain, err := ParseIdentifier(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("https://%s/.well-known/nostr.json?name=%s", domain, url.QueryEscape(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", endpoint, resp.Status)
+	}
+
+	var doc wellKnown
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", endpoint, err)
+	}
+
+	pubKey, ok := doc.Names[name]
+	if !ok {
+		return nil, fmt.Errorf("%s@%s: %w", name, domain, ErrNameNotFound)
+	}
+	pubKey = strings.ToLower(pubKey)
+	if !nostrlib.IsValidPublicKeyHex(pubKey) {
+		return nil, fmt.Errorf("%s@%s: invalid public key %q in nostr.json", name, domain, pubKey)
+	}
+	return &Result{Name: name, Domain: domain, PubKey: pubKey, Relays: doc.Relays[pubKey]}, nil
+}
+
+func Verify(ctx context.Context, identifier, pubKey string) (*Result, error) {
+	result, err := Lookup(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(result.PubKey, pubKey) {
+		return result, fmt.Errorf("%w (%s)", ErrMismatch, result.PubKey)
+	}
+	return result, nil
+}
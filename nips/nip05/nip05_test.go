@@ -0,0 +1,81 @@
+package nip05
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const alicePubKey = "b0635d6a9851d3aed0cd6c495b282167acf761729078d975fc341b22650b07b9"
+
+func newWellKnownServer(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/nostr.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"names":{"alice":"` + alicePubKey + `"},"relays":{"` + alicePubKey + `":["wss://relay.example.com"]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	previous := httpClient
+	client := server.Client()
+	client.CheckRedirect = previous.CheckRedirect
+	httpClient = client
+	t.Cleanup(func() { httpClient = previous })
+
+	return strings.TrimPrefix(server.URL, "https://")
+}
+
+func TestLookup(t *testing.T) {
+	domain := newWellKnownServer(t)
+
+	result, err := Lookup(context.Background(), "Alice@"+domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.PubKey != alicePubKey || len(result.Relays) != 1 || result.Relays[0] != "wss://relay.example.com" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	if _, err := Lookup(context.Background(), "bob@"+domain); !errors.Is(err, ErrNameNotFound) {
+		t.Fatalf("expected ErrNameNotFound, got %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	domain := newWellKnownServer(t)
+
+	if _, err := Verify(context.Background(), "alice@"+domain, alicePubKey); err != nil {
+		t.Fatalf("expected alice to verify: %v", err)
+	}
+	other := strings.Repeat("a", 64)
+	if _, err := Verify(context.Background(), "alice@"+domain, other); !errors.Is(err, ErrMismatch) {
+		t.Fatalf("expected ErrMismatch, got %v", err)
+	}
+}
+
+func TestParseIdentifier(t *testing.T) {
+	tests := []struct {
+		input, name, domain string
+		valid               bool
+	}{
+		{"alice@example.com", "alice", "example.com", true},
+		{"example.com", "_", "example.com", true},
+		{"alice@localhost", "", "", false},
+		{"@example.com", "", "", false},
+		{"a@b@example.com", "", "", false},
+	}
+	for _, test := range tests {
+		name, domain, err := ParseIdentifier(test.input)
+		if (err == nil) != test.valid || name != test.name || domain != test.domain {
+			t.Fatalf("%s: got %q %q %v", test.input, name, domain, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package nip09
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip01"
+	nostrkeys "nostr-cli/nostr"
+)
+
+const KindDeletion = 5
+
+// Target is an event to delete. ByAddress deletes an addressable event by its
+// coordinate, which covers every version up to the deletion request; otherwise
+// only this exact event (such as one old article revision) is deleted.
+type Target struct {
+	Event     *nostrlib.Event
+	ByAddress bool
+}
+
+func (t Target) Coordinate() string {
+	return fmt.Sprintf("%d:%s:%s", t.Event.Kind, t.Event.PubKey, t.Event.Tags.GetD())
+}
+
+// CheckAuthor refuses targets that pubKey did not write, since relays ignore
+// deletion requests for other people's events anyway.
+func CheckAuthor(targets []Target, pubKey string) error {
+	for _, target := range targets {
+		if target.Event.PubKey != pubKey {
+			return fmt.Errorf("event %s was written by %s, not by this profile", target.Event.ID, target.Event.PubKey)
+		}
+	}
+	return nil
+}
+
+// DeletionTags references each target by e tag, or by a tag when deleting by
+// address, followed by one k tag per deleted kind.
+func DeletionTags(targets []Target) nostrlib.Tags {
+	var tags, kinds nostrlib.Tags
+	for _, target := range targets {
+		if target.ByAddress && relay.IsAddressable(target.Event.Kind) {
+			tags = nostrkeys.AppendUniqueTag(tags, nostrlib.Tag{"a", target.Coordinate()})
+		} else {
+			tags = nostrkeys.AppendUniqueTag(tags, nostrlib.Tag{"e", target.Event.ID})
+		}
+		kinds = nostrkeys.AppendUniqueTag(kinds, nostrlib.Tag{"k", strconv.Itoa(target.Event.Kind)})
+	}
+	return append(tags, kinds...)
+}
+
+// PublishDeletion asks the profile's relays plus extra ones to delete
+// targets, with an optional human-readable reason.
+func PublishDeletion(ctx context.Context, profile *nostrkeys.Profile, sk string, targets []Target, reason string, extra []string, policy relay.Policy) (*nostrlib.Event, []relay.PublishResult, error) {
+	if len(targets) == 0 {
+		return nil, nil, errors.New("nothing to delete")
+	}
+	if err := CheckAuthor(targets, profile.PublicKey); err != nil {
+		return nil, nil, err
+	}
+
+	ev := &nostrlib.Event{Kind: KindDeletion, Content: reason, Tags: DeletionTags(targets)}
+	results, err := nip01.PublishEvent(ctx, profile, sk, ev, extra, policy)
+	if results == nil && err != nil {
+		return nil, nil, err
+	}
+	return ev, results, err
+}
+
+// Remaining queries one relay and returns the targets it still serves, by
+// event id or coordinate. Versions of an addressable event newer than the
+// deletion request are not affected by it and so are not counted.
+func Remaining(ctx context.Context, url string, deletion *nostrlib.Event, targets []Target, auth *relay.Auth) ([]string, error) {
+	var remaining []string
+	for _, target := range targets {
+		filter := nostrlib.Filter{IDs: []string{target.Event.ID}}
+		label := target.Event.ID
+		if target.ByAddress && relay.IsAddressable(target.Event.Kind) {
+			until := deletion.CreatedAt
+			filter = nostrlib.Filter{
+				Kinds:   []int{target.Event.Kind},
+				Authors: []string{target.Event.PubKey},
+				Tags:    nostrlib.TagMap{"d": []string{target.Event.Tags.GetD()}},
+				Until:   &until,
+			}
+			label = target.Coordinate()
+		}
+		events, err := relay.QueryRelay(ctx, url, filter, auth)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > 0 {
+			remaining = append(remaining, label)
+		}
+	}
+	return remaining, nil
+}
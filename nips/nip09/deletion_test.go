@@ -0,0 +1,69 @@
+package nip09
+
+import (
+	"reflect"
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+func TestDeletionTags(t *testing.T) {
+	note := &nostrlib.Event{ID: "note", PubKey: "alice", Kind: 1}
+	other := &nostrlib.Event{ID: "other", PubKey: "alice", Kind: 1}
+	article := &nostrlib.Event{ID: "article", PubKey: "alice", Kind: 30023, Tags: nostrlib.Tags{{"d", "post"}}}
+	revision := &nostrlib.Event{ID: "revision", PubKey: "alice", Kind: 30023, Tags: nostrlib.Tags{{"d", "post"}}}
+
+	tags := DeletionTags([]Target{
+		{Event: note},
+		{Event: other},
+		{Event: article, ByAddress: true},
+		{Event: revision},
+		{Event: note},
+	})
+	expected := nostrlib.Tags{
+		{"e", "note"},
+		{"e", "other"},
+		{"a", "30023:alice:post"},
+		{"e", "revision"},
+		{"k", "1"},
+		{"k", "30023"},
+	}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("expected %v, got %v", expected, tags)
+	}
+}
+
+func TestDeletionTagsWithOverlappingValues(t *testing.T) {
+	article := &nostrlib.Event{ID: "article", PubKey: "alice", Kind: 30023, Tags: nostrlib.Tags{{"d", "foobar"}}}
+	shorter := &nostrlib.Event{ID: "shorter", PubKey: "alice", Kind: 30023, Tags: nostrlib.Tags{{"d", "foo"}}}
+	follows := &nostrlib.Event{ID: "follows", PubKey: "alice", Kind: 3}
+
+	tags := DeletionTags([]Target{
+		{Event: article, ByAddress: true},
+		{Event: shorter, ByAddress: true},
+		{Event: follows},
+	})
+	expected := nostrlib.Tags{
+		{"a", "30023:alice:foobar"},
+		{"a", "30023:alice:foo"},
+		{"e", "follows"},
+		{"k", "30023"},
+		{"k", "3"},
+	}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("expected %v, got %v", expected, tags)
+	}
+}
+
+func TestCheckAuthor(t *testing.T) {
+	targets := []Target{
+		{Event: &nostrlib.Event{ID: "mine", PubKey: "alice", Kind: 1}},
+		{Event: &nostrlib.Event{ID: "theirs", PubKey: "bob", Kind: 1}},
+	}
+	if err := CheckAuthor(targets[:1], "alice"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CheckAuthor(targets, "alice"); err == nil {
+		t.Fatalf("expected an error for bob's event")
+	}
+}
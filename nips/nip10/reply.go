@@ -0,0 +1,65 @@
+package nip10
+
+import (
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+// ReplyTags builds the marked e tags and p tags for a reply to parent.
+// relayHint is where parent can be found; self is left out of the p tags.
+func ReplyTags(parent *nostrlib.Event, relayHint, self string) nostrlib.Tags {
+	var tags nostrlib.Tags
+	if root := RootTag(parent); root != nil {
+		tags = append(tags, root, nostrlib.Tag{"e", parent.ID, relayHint, "reply"})
+	} else {
+		tags = append(tags, nostrlib.Tag{"e", parent.ID, relayHint, "root"})
+	}
+
+	seen := map[string]bool{self: true}
+	addPubKey := func(pubKey string) {
+		if pubKey == "" || seen[pubKey] {
+			return
+		}
+		seen[pubKey] = true
+		tags = append(tags, nostrlib.Tag{"p", pubKey})
+	}
+	for _, tag := range parent.Tags {
+		if len(tag) >= 2 && tag[0] == "p" {
+			addPubKey(tag[1])
+		}
+	}
+	addPubKey(parent.PubKey)
+	return tags
+}
+
+// RootTag returns the thread root referenced by ev as a marked root tag, or
+// nil when ev is itself a root, i.e. has no e tags besides mentions. Events
+// using the deprecated positional scheme have their root in the first e tag,
+// and a reply that marks only its parent is treated as replying to the root.
+func RootTag(ev *nostrlib.Event) nostrlib.Tag {
+	var first, reply nostrlib.Tag
+	for _, tag := range ev.Tags {
+		if len(tag) < 2 || tag[0] != "e" {
+			continue
+		}
+		if len(tag) >= 4 && tag[3] == "root" {
+			return nostrlib.Tag{"e", tag[1], tag[2], "root"}
+		}
+		if first == nil && (len(tag) < 4 || tag[3] == "") {
+			first = tag
+		}
+		if reply == nil && len(tag) >= 4 && tag[3] == "reply" {
+			reply = tag
+		}
+	}
+	if first == nil {
+		first = reply
+	}
+	if first == nil {
+		return nil
+	}
+	hint := ""
+	if len(first) >= 3 {
+		hint = first[2]
+	}
+	return nostrlib.Tag{"e", first[1], hint, "root"}
+}
@@ -0,0 +1,87 @@
+package nip10
+
+import (
+	"reflect"
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+func TestReplyTags(t *testing.T) {
+	const self = "self"
+	tests := []struct {
+		name     string
+		parent   nostrlib.Event
+		expected nostrlib.Tags
+	}{
+		{
+			name:   "reply to a root note",
+			parent: nostrlib.Event{ID: "root", PubKey: "alice", Tags: nostrlib.Tags{{"t", "nostr"}}},
+			expected: nostrlib.Tags{
+				{"e", "root", "wss://hint", "root"},
+				{"p", "alice"},
+			},
+		},
+		{
+			name: "reply to a marked reply",
+			parent: nostrlib.Event{ID: "mid", PubKey: "bob", Tags: nostrlib.Tags{
+				{"e", "root", "wss://root", "root"},
+				{"e", "other", "", "reply"},
+				{"p", "alice"},
+				{"p", self},
+				{"p", "bob"},
+			}},
+			expected: nostrlib.Tags{
+				{"e", "root", "wss://root", "root"},
+				{"e", "mid", "wss://hint", "reply"},
+				{"p", "alice"},
+				{"p", "bob"},
+			},
+		},
+		{
+			name: "reply to a positional reply",
+			parent: nostrlib.Event{ID: "mid", PubKey: "bob", Tags: nostrlib.Tags{
+				{"e", "root"},
+				{"e", "other"},
+				{"p", "alice"},
+			}},
+			expected: nostrlib.Tags{
+				{"e", "root", "", "root"},
+				{"e", "mid", "wss://hint", "reply"},
+				{"p", "alice"},
+				{"p", "bob"},
+			},
+		},
+		{
+			name: "reply to a reply that only marks its parent",
+			parent: nostrlib.Event{ID: "mid", PubKey: "bob", Tags: nostrlib.Tags{
+				{"e", "quoted", "", "mention"},
+				{"e", "root", "wss://root", "reply"},
+				{"p", "alice"},
+			}},
+			expected: nostrlib.Tags{
+				{"e", "root", "wss://root", "root"},
+				{"e", "mid", "wss://hint", "reply"},
+				{"p", "alice"},
+				{"p", "bob"},
+			},
+		},
+		{
+			name: "reply to a note that only mentions events",
+			parent: nostrlib.Event{ID: "note", PubKey: "bob", Tags: nostrlib.Tags{
+				{"e", "quoted", "", "mention"},
+			}},
+			expected: nostrlib.Tags{
+				{"e", "note", "wss://hint", "root"},
+				{"p", "bob"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		tags := ReplyTags(&test.parent, "wss://hint", self)
+		if !reflect.DeepEqual(tags, test.expected) {
+			t.Fatalf("%s: expected %v got %v", test.name, test.expected, tags)
+		}
+	}
+}
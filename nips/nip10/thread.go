@@ -0,0 +1,97 @@
+package nip10
+
+import (
+	"sort"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+type Node struct {
+	Event   *nostrlib.Event
+	Replies []*Node
+}
+
+// ParentID returns the id of the event that ev replies to, or "" for a root.
+// Marked tags win; otherwise the last of the positional e tags is the parent.
+func ParentID(ev *nostrlib.Event) string {
+	var root, last string
+	for _, tag := range ev.Tags {
+		if len(tag) < 2 || tag[0] != "e" {
+			continue
+		}
+		marker := ""
+		if len(tag) >= 4 {
+			marker = tag[3]
+		}
+		switch marker {
+		case "reply":
+			return tag[1]
+		case "root":
+			root = tag[1]
+		case "":
+			last = tag[1]
+		}
+	}
+	if root != "" {
+		return root
+	}
+	return last
+}
+
+// BuildTree arranges events under root by their parent ids, oldest reply
+// first. Replies whose parent was not fetched hang off the root so nothing
+// in the thread is dropped.
+func BuildTree(root *nostrlib.Event, events []*nostrlib.Event) *Node {
+	top := &Node{Event: root}
+	nodes := map[string]*Node{root.ID: top}
+	for _, ev := range events {
+		if _, ok := nodes[ev.ID]; !ok {
+			nodes[ev.ID] = &Node{Event: ev}
+		}
+	}
+
+	for _, node := range nodes {
+		if node == top {
+			continue
+		}
+		parent, ok := nodes[ParentID(node.Event)]
+		if !ok || createsCycle(nodes, top, node, parent) {
+			parent = top
+		}
+		parent.Replies = append(parent.Replies, node)
+	}
+
+	var sortReplies func(*Node)
+	sortReplies = func(node *Node) {
+		sort.Slice(node.Replies, func(i, j int) bool {
+			a, b := node.Replies[i].Event, node.Replies[j].Event
+			if a.CreatedAt != b.CreatedAt {
+				return a.CreatedAt < b.CreatedAt
+			}
+			return a.ID < b.ID
+		})
+		for _, reply := range node.Replies {
+			sortReplies(reply)
+		}
+	}
+	sortReplies(top)
+	return top
+}
+
+// createsCycle reports whether parent descends from node through the parent
+// links, which only malformed events can produce.
+func createsCycle(nodes map[string]*Node, top, node, parent *Node) bool {
+	seen := map[*Node]bool{}
+	for current := parent; current != top && !seen[current]; {
+		if current == node {
+			return true
+		}
+		seen[current] = true
+		next, ok := nodes[ParentID(current.Event)]
+		if !ok {
+			return false
+		}
+		current = next
+	}
+	return false
+}
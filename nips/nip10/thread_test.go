@@ -0,0 +1,60 @@
+package nip10
+
+import (
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+func TestParentID(t *testing.T) {
+	tests := []struct {
+		tags     nostrlib.Tags
+		expected string
+	}{
+		{nostrlib.Tags{}, ""},
+		{nostrlib.Tags{{"e", "root", "", "root"}}, "root"},
+		{nostrlib.Tags{{"e", "root", "", "root"}, {"e", "parent", "", "reply"}}, "parent"},
+		{nostrlib.Tags{{"e", "root"}}, "root"},
+		{nostrlib.Tags{{"e", "root"}, {"e", "mention"}, {"e", "parent"}}, "parent"},
+		{nostrlib.Tags{{"e", "root", "", "root"}, {"e", "quoted", "", "mention"}}, "root"},
+	}
+	for _, test := range tests {
+		if parent := ParentID(&nostrlib.Event{Tags: test.tags}); parent != test.expected {
+			t.Fatalf("%v: expected %q got %q", test.tags, test.expected, parent)
+		}
+	}
+}
+
+func TestBuildTree(t *testing.T) {
+	root := &nostrlib.Event{ID: "root", CreatedAt: 1}
+	events := []*nostrlib.Event{
+		{ID: "b", CreatedAt: 3, Tags: nostrlib.Tags{{"e", "root", "", "root"}}},
+		{ID: "a", CreatedAt: 2, Tags: nostrlib.Tags{{"e", "root", "", "root"}}},
+		{ID: "a1", CreatedAt: 4, Tags: nostrlib.Tags{{"e", "root"}, {"e", "a"}}},
+		{ID: "orphan", CreatedAt: 5, Tags: nostrlib.Tags{{"e", "root", "", "root"}, {"e", "missing", "", "reply"}}},
+		{ID: "x", CreatedAt: 6, Tags: nostrlib.Tags{{"e", "y", "", "reply"}}},
+		{ID: "y", CreatedAt: 7, Tags: nostrlib.Tags{{"e", "x", "", "reply"}}},
+		root,
+	}
+
+	tree := BuildTree(root, events)
+	var got []string
+	var walk func(*Node, string)
+	walk = func(node *Node, prefix string) {
+		got = append(got, prefix+node.Event.ID)
+		for _, reply := range node.Replies {
+			walk(reply, prefix+"-")
+		}
+	}
+	walk(tree, "")
+
+	expected := []string{"root", "-a", "--a1", "-b", "-orphan", "-x", "-y"}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected %v got %v", expected, got)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package nip18
+
+import (
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+// QuoteTags returns the q tag for quoting ev, plus a p tag for its author.
+func QuoteTags(ev *nostrlib.Event, relayHint string) nostrlib.Tags {
+	return nostrlib.Tags{
+		{"q", ev.ID, relayHint, ev.PubKey},
+		{"p", ev.PubKey},
+	}
+}
+
+// QuoteContent appends uri to content unless the note already mentions it.
+func QuoteContent(content, uri string) string {
+	if strings.Contains(content, uri) {
+		return content
+	}
+	if content == "" {
+		return uri
+	}
+	return content + "\n\n" + uri
+}
@@ -0,0 +1,70 @@
+package nip18
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip01"
+	nostrkeys "nostr-cli/nostr"
+)
+
+const (
+	KindRepost        = 6
+	KindGenericRepost = 16
+)
+
+// Repost builds the unsigned repost of target: kind 6 for notes and kind 16
+// with a k tag for anything else. The target's JSON is embedded unless it is
+// a protected (NIP-70) event, which must not be rebroadcast.
+func Repost(target *nostrlib.Event, relayHint string) (*nostrlib.Event, error) {
+	ev := &nostrlib.Event{Kind: KindRepost}
+	if target.Kind != 1 {
+		ev.Kind = KindGenericRepost
+	}
+
+	if target.Tags.GetFirst([]string{"-"}) == nil {
+		raw, err := json.Marshal(target)
+		if err != nil {
+			return nil, err
+		}
+		ev.Content = string(raw)
+	}
+
+	ev.Tags = RepostTags(target, relayHint)
+	return ev, nil
+}
+
+// RepostTags references target with e and p tags, an a tag for addressable
+// events, and the k tag generic reposts carry.
+func RepostTags(target *nostrlib.Event, relayHint string) nostrlib.Tags {
+	tags := nostrlib.Tags{
+		{"e", target.ID, relayHint},
+		{"p", target.PubKey},
+	}
+	if relay.IsAddressable(target.Kind) {
+		tags = append(tags, nostrlib.Tag{"a", fmt.Sprintf("%d:%s:%s", target.Kind, target.PubKey, target.Tags.GetD()), relayHint})
+	}
+	if target.Kind != 1 {
+		tags = append(tags, nostrlib.Tag{"k", strconv.Itoa(target.Kind)})
+	}
+	return tags
+}
+
+// PublishRepost reposts target to the profile's relays plus extra ones such
+// as the target author's inbox.
+func PublishRepost(ctx context.Context, profile *nostrkeys.Profile, sk string, target *nostrlib.Event, relayHint string, extra []string, policy relay.Policy) (*nostrlib.Event, []relay.PublishResult, error) {
+	ev, err := Repost(target, relayHint)
+	if err != nil {
+		return nil, nil, err
+	}
+	results, err := nip01.PublishEvent(ctx, profile, sk, ev, extra, policy)
+	if results == nil && err != nil {
+		return nil, nil, err
+	}
+	return ev, results, err
+}
@@ -0,0 +1,45 @@
+package nip18
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+func TestRepost(t *testing.T) {
+	note := &nostrlib.Event{ID: "note", PubKey: "alice", Kind: 1, Content: "hello", Tags: nostrlib.Tags{}}
+	repost, err := Repost(note, "wss://hint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repost.Kind != KindRepost {
+		t.Fatalf("expected kind 6, got %d", repost.Kind)
+	}
+	if !reflect.DeepEqual(repost.Tags, nostrlib.Tags{{"e", "note", "wss://hint"}, {"p", "alice"}}) {
+		t.Fatalf("unexpected tags %v", repost.Tags)
+	}
+	var embedded nostrlib.Event
+	if err := json.Unmarshal([]byte(repost.Content), &embedded); err != nil || embedded.ID != "note" {
+		t.Fatalf("expected embedded note, got %q (%v)", repost.Content, err)
+	}
+
+	article := &nostrlib.Event{ID: "article", PubKey: "bob", Kind: 30023, Tags: nostrlib.Tags{{"d", "post"}, {"-"}}}
+	repost, err = Repost(article, "wss://hint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := nostrlib.Tags{
+		{"e", "article", "wss://hint"},
+		{"p", "bob"},
+		{"a", "30023:bob:post", "wss://hint"},
+		{"k", "30023"},
+	}
+	if repost.Kind != KindGenericRepost || !reflect.DeepEqual(repost.Tags, expected) {
+		t.Fatalf("unexpected generic repost kind %d tags %v", repost.Kind, repost.Tags)
+	}
+	if repost.Content != "" {
+		t.Fatalf("protected events must not be embedded, got %q", repost.Content)
+	}
+}
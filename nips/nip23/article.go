@@ -3,6 +3,7 @@ package nip23
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	nostrlib "github.com/nbd-wtf/go-nostr"
 
 	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip01"
 	nostrkeys "nostr-cli/nostr"
 )
 
@@ -26,19 +28,19 @@ type PublishOptions struct {
 	Identifier    string
 }
 
-func PublishArticle(ctx context.Context, profile *nostrkeys.Profile, sk string, opts PublishOptions) error {
+func PublishArticle(ctx context.Context, profile *nostrkeys.Profile, sk string, opts PublishOptions, policy relay.Policy) (*nostrlib.Event, []relay.PublishResult, error) {
 	var body string
 	switch {
 	case strings.TrimSpace(opts.FilePath) != "":
 		content, err := os.ReadFile(opts.FilePath)
 		if err != nil {
-			return fmt.Errorf("reading article file: %w", err)
+			return nil, nil, fmt.Errorf("reading article file: %w", err)
 		}
 		body = strings.TrimPrefix(string(content), "\ufeff")
 	case opts.InlineContent != "":
 		body = strings.TrimPrefix(opts.InlineContent, "\ufeff")
 	default:
-		return fmt.Errorf("article content is required")
+		return nil, nil, fmt.Errorf("article content is required")
 	}
 	frontMatter, strippedBody := extractFrontMatter(body)
 	body = strippedBody
@@ -82,7 +84,7 @@ func PublishArticle(ctx context.Context, profile *nostrkeys.Profile, sk string,
 	}
 	ev.Tags = append(ev.Tags, nostrlib.Tag{"published_at", publishedAt})
 
-	for _, relayURL := range profile.Relays {
+	for _, relayURL := range profile.WriteRelays() {
 		relayURL = strings.TrimSpace(relayURL)
 		if relayURL == "" {
 			continue
@@ -90,12 +92,30 @@ func PublishArticle(ctx context.Context, profile *nostrkeys.Profile, sk string,
 		ev.Tags = append(ev.Tags, nostrlib.Tag{"r", relayURL})
 	}
 
-	if err := ev.Sign(sk); err != nil {
-		return err
+	results, err := nip01.PublishEvent(ctx, profile, sk, &ev, nil, policy)
+	if results == nil && err != nil {
+		return nil, nil, err
 	}
+	return &ev, results, err
+}
 
-	relay.PublishToRelays(ctx, profile.Relays, ev)
-	return nil
+func FetchArticle(ctx context.Context, relays []string, pubKey, identifier string, auth *relay.Auth) (*nostrlib.Event, error) {
+	if pubKey == "" {
+		return nil, errors.New("a public key is required")
+	}
+	filter := nostrlib.Filter{
+		Kinds:   []int{30023},
+		Authors: []string{pubKey},
+		Tags:    nostrlib.TagMap{"d": []string{identifier}},
+	}
+	ev, err := relay.FetchLatest(ctx, relays, filter, auth)
+	if err != nil {
+		if errors.Is(err, relay.ErrNotFound) {
+			return nil, fmt.Errorf("article '%s' not found on the queried relays", identifier)
+		}
+		return nil, err
+	}
+	return ev, nil
 }
 
 func fallbackValue(values ...string) string {
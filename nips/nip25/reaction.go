@@ -0,0 +1,153 @@
+package nip25
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip01"
+	nostrkeys "nostr-cli/nostr"
+)
+
+const KindReaction = 7
+
+var shortcodePattern = regexp.MustCompile(`^:([a-zA-Z0-9_]+):$`)
+
+// Shortcode returns the NIP-30 shortcode in content such as ":soapbox:".
+func Shortcode(content string) (string, bool) {
+	match := shortcodePattern.FindStringSubmatch(content)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// ReactionTags references target with e, p and k tags, plus an a tag for
+// addressable events so the reaction follows later versions.
+func ReactionTags(target *nostrlib.Event, relayHint string) nostrlib.Tags {
+	withHint := func(tag nostrlib.Tag) nostrlib.Tag {
+		if relayHint == "" {
+			return tag
+		}
+		return append(tag, relayHint)
+	}
+	tags := nostrlib.Tags{
+		{"e", target.ID, relayHint, target.PubKey},
+		withHint(nostrlib.Tag{"p", target.PubKey}),
+	}
+	if relay.IsAddressable(target.Kind) {
+		tags = append(tags, withHint(nostrlib.Tag{"a", Address(target.Kind, target.PubKey, target.Tags.GetD())}))
+	}
+	return append(tags, nostrlib.Tag{"k", strconv.Itoa(target.Kind)})
+}
+
+// PublishReaction reacts to target with content ("+", "-", an emoji, or a
+// :shortcode: whose image is emojiURL) and sends it to the profile's relays
+// plus extra ones such as the target author's inbox.
+func PublishReaction(ctx context.Context, profile *nostrkeys.Profile, sk string, target *nostrlib.Event, content, emojiURL, relayHint string, extra []string, policy relay.Policy) (*nostrlib.Event, []relay.PublishResult, error) {
+	if content == "" {
+		content = "+"
+	}
+	tags := ReactionTags(target, relayHint)
+	if shortcode, ok := Shortcode(content); ok {
+		if emojiURL == "" {
+			return nil, nil, fmt.Errorf("custom emoji %s needs an image URL", content)
+		}
+		tags = append(tags, nostrlib.Tag{"emoji", shortcode, emojiURL})
+	} else if emojiURL != "" {
+		return nil, nil, fmt.Errorf("an emoji URL needs a :shortcode: reaction, got %q", content)
+	}
+
+	ev := &nostrlib.Event{Kind: KindReaction, Content: content, Tags: tags}
+	results, err := nip01.PublishEvent(ctx, profile, sk, ev, extra, policy)
+	if results == nil && err != nil {
+		return nil, nil, err
+	}
+	return ev, results, err
+}
+
+type Summary struct {
+	Content  string   `json:"content"`
+	EmojiURL string   `json:"emoji_url,omitempty"`
+	Count    int      `json:"count"`
+	Authors  []string `json:"authors"`
+}
+
+// Aggregate counts reactions to target, an event ID or the "kind:pubkey:d"
+// address of an addressable event, by content. Each author counts once per
+// content, and an empty content is treated as "+".
+func Aggregate(target string, reactions []*nostrlib.Event) []Summary {
+	byContent := make(map[string]*Summary)
+	seen := make(map[string]bool)
+	for _, ev := range reactions {
+		if ev.Kind != KindReaction || !targets(ev, target) {
+			continue
+		}
+		content := ev.Content
+		if content == "" {
+			content = "+"
+		}
+		key := ev.PubKey + "\x00" + content
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		summary, ok := byContent[content]
+		if !ok {
+			summary = &Summary{Content: content}
+			if shortcode, isEmoji := Shortcode(content); isEmoji {
+				if tag := ev.Tags.GetFirst([]string{"emoji", shortcode}); tag != nil && len(*tag) >= 3 {
+					summary.EmojiURL = (*tag)[2]
+				}
+			}
+			byContent[content] = summary
+		}
+		summary.Count++
+		summary.Authors = append(summary.Authors, ev.PubKey)
+	}
+
+	summaries := make([]Summary, 0, len(byContent))
+	for _, summary := range byContent {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Count != summaries[j].Count {
+			return summaries[i].Count > summaries[j].Count
+		}
+		return summaries[i].Content < summaries[j].Content
+	})
+	return summaries
+}
+
+// targets checks the last e tag, which NIP-25 reserves for the reacted event,
+// or for an address any a tag, which covers reactions to every version.
+func targets(ev *nostrlib.Event, target string) bool {
+	if strings.Contains(target, ":") {
+		for _, tag := range ev.Tags {
+			if len(tag) >= 2 && tag[0] == "a" && tag[1] == target {
+				return true
+			}
+		}
+		return false
+	}
+	var last string
+	for _, tag := range ev.Tags {
+		if len(tag) >= 2 && tag[0] == "e" {
+			last = tag[1]
+		}
+	}
+	return last == target
+}
+
+// Address returns the "kind:pubkey:d" coordinate reactions to an addressable
+// event carry in their a tag.
+func Address(kind int, pubKey, identifier string) string {
+	return fmt.Sprintf("%d:%s:%s", kind, pubKey, identifier)
+}
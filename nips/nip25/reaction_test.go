@@ -0,0 +1,59 @@
+package nip25
+
+import (
+	"reflect"
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+func TestReactionTags(t *testing.T) {
+	article := &nostrlib.Event{ID: "id", PubKey: "alice", Kind: 30023, Tags: nostrlib.Tags{{"d", "post"}}}
+	expected := nostrlib.Tags{
+		{"e", "id", "wss://hint", "alice"},
+		{"p", "alice", "wss://hint"},
+		{"a", "30023:alice:post", "wss://hint"},
+		{"k", "30023"},
+	}
+	if tags := ReactionTags(article, "wss://hint"); !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("expected %v got %v", expected, tags)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	reaction := func(pubKey, content string, tags ...nostrlib.Tag) *nostrlib.Event {
+		return &nostrlib.Event{Kind: KindReaction, PubKey: pubKey, Content: content, Tags: append(nostrlib.Tags{{"e", "target"}}, tags...)}
+	}
+	events := []*nostrlib.Event{
+		reaction("alice", "+"),
+		reaction("bob", ""),
+		reaction("bob", "+"),
+		reaction("carol", ":soapbox:", nostrlib.Tag{"emoji", "soapbox", "https://example.com/soapbox.png"}),
+		reaction("dave", "-"),
+		{Kind: KindReaction, PubKey: "erin", Content: "+", Tags: nostrlib.Tags{{"e", "target"}, {"e", "other"}}},
+	}
+
+	expected := []Summary{
+		{Content: "+", Count: 2, Authors: []string{"alice", "bob"}},
+		{Content: "-", Count: 1, Authors: []string{"dave"}},
+		{Content: ":soapbox:", EmojiURL: "https://example.com/soapbox.png", Count: 1, Authors: []string{"carol"}},
+	}
+	if summaries := Aggregate("target", events); !reflect.DeepEqual(summaries, expected) {
+		t.Fatalf("expected %+v got %+v", expected, summaries)
+	}
+}
+
+func TestAggregateAddress(t *testing.T) {
+	address := Address(30023, "alice", "post")
+	events := []*nostrlib.Event{
+		{Kind: KindReaction, PubKey: "bob", Content: "+", Tags: nostrlib.Tags{{"e", "current"}, {"a", address}}},
+		{Kind: KindReaction, PubKey: "carol", Content: "+", Tags: nostrlib.Tags{{"e", "old-version"}, {"a", address, "wss://hint"}}},
+		{Kind: KindReaction, PubKey: "dave", Content: "+", Tags: nostrlib.Tags{{"e", "current"}}},
+		{Kind: KindReaction, PubKey: "erin", Content: "+", Tags: nostrlib.Tags{{"e", "x"}, {"a", "30023:alice:other"}}},
+	}
+
+	expected := []Summary{{Content: "+", Count: 2, Authors: []string{"bob", "carol"}}}
+	if summaries := Aggregate(address, events); !reflect.DeepEqual(summaries, expected) {
+		t.Fatalf("expected %+v got %+v", expected, summaries)
+	}
+}
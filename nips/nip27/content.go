@@ -0,0 +1,114 @@
+package nip27
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	nostrkeys "nostr-cli/nostr"
+)
+
+// Resolver maps a NIP-05 handle such as bob@example.com to a public key.
+type Resolver func(identifier string) (string, error)
+
+var tokenPattern = regexp.MustCompile(`(?i)(@|nostr:)((?:npub|nprofile|note|nevent|naddr)1[02-9ac-hj-np-z]+)|@([a-z0-9._-]+@[a-z0-9-]+(?:\.[a-z0-9-]+)+)|#([\p{L}\p{N}_]+)`)
+
+// Process rewrites @npub, @nprofile and NIP-05 handles in content to nostr:
+// URIs and returns the p, q and t tags implied by the mentions, event
+// references and hashtags it contains. NIP-05 handles are only rewritten when
+// resolve is set.
+func Process(content string, resolve Resolver) (string, nostrlib.Tags, error) {
+	var out strings.Builder
+	var tags nostrlib.Tags
+	last := 0
+	for _, match := range tokenPattern.FindAllStringSubmatchIndex(content, -1) {
+		start, end := match[0], match[1]
+		token := content[start:end]
+		if !strings.HasPrefix(strings.ToLower(token), "nostr:") && !atBoundary(content, start) {
+			continue
+		}
+
+		replacement := token
+		switch {
+		case match[4] >= 0:
+			entity := strings.ToLower(content[match[4]:match[5]])
+			ref, err := nostrkeys.DecodeReference(entity)
+			if err != nil {
+				continue
+			}
+			replacement = "nostr:" + entity
+			tags = appendReferenceTag(tags, ref)
+		case match[6] >= 0:
+			if resolve == nil {
+				continue
+			}
+			handle := content[match[6]:match[7]]
+			pubKey, err := resolve(handle)
+			if err != nil {
+				return "", nil, fmt.Errorf("resolving @%s: %w", handle, err)
+			}
+			npub, err := nostrkeys.HexToNpub(pubKey)
+			if err != nil {
+				return "", nil, err
+			}
+			replacement = "nostr:" + npub
+			tags = nostrkeys.AppendUniqueTag(tags, nostrlib.Tag{"p", pubKey})
+		case match[8] >= 0:
+			hashtag := content[match[8]:match[9]]
+			if isNumeric(hashtag) {
+				continue
+			}
+			tags = nostrkeys.AppendUniqueTag(tags, nostrlib.Tag{"t", strings.ToLower(hashtag)})
+		}
+
+		out.WriteString(content[last:start])
+		out.WriteString(replacement)
+		last = end
+	}
+	out.WriteString(content[last:])
+	return out.String(), tags, nil
+}
+
+func appendReferenceTag(tags nostrlib.Tags, ref *nostrkeys.Reference) nostrlib.Tags {
+	hint := ""
+	if len(ref.Relays) > 0 {
+		hint = ref.Relays[0]
+	}
+	var tag nostrlib.Tag
+	switch ref.Type {
+	case "npub", "nprofile":
+		tag = nostrlib.Tag{"p", ref.PubKey, hint}
+	case "note", "nevent":
+		tag = nostrlib.Tag{"q", ref.EventID, hint, ref.PubKey}
+	case "naddr":
+		tag = nostrlib.Tag{"q", fmt.Sprintf("%d:%s:%s", *ref.Kind, ref.PubKey, ref.Identifier), hint}
+	default:
+		return tags
+	}
+	for len(tag) > 2 && tag[len(tag)-1] == "" {
+		tag = tag[:len(tag)-1]
+	}
+	return nostrkeys.AppendUniqueTag(tags, tag)
+}
+
+// atBoundary keeps @ and # tokens inside words, URLs and emails untouched.
+func atBoundary(content string, start int) bool {
+	prev, _ := utf8.DecodeLastRuneInString(content[:start])
+	if prev == utf8.RuneError {
+		return true
+	}
+	return !unicode.IsLetter(prev) && !unicode.IsDigit(prev) && !strings.ContainsRune("_/#&=?@.:-", prev)
+}
+
+func isNumeric(value string) bool {
+	for _, r := range value {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,76 @@
+package nip27
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+const (
+	alice     = "7e7e9c42a91bfef19fa929e5fda1b72e0ebc1a4c1141673e2794234d86addf4e"
+	aliceNpub = "npub10elfcs4fr0l0r8af98jlmgdh9c8tcxjvz9qkw038js35mp4dma8qzvjptg"
+	bob       = "3bf0c63fcb93463407af97a5e5ee64fa883d107ef9e558472c4eb9aaaefa459d"
+	bobNpub   = "npub180cvv07tjdrrgpa0j7j7tmnyl2yr6yr7l8j4s3evf6u64th6gkwsyjh6w6"
+	nevent    = "nevent1qqsy2vn0t45k92c78n2zfe6ccvqzhpn977cd3h8wnl579zxhw5dvr9qpzpmhxue69uhkyctwv9hxztnrdaksygrl54h466tz4v0re4pyuavvxqptsejl0vxcmnhfl60z3rth2x4m3q04ndyp"
+)
+
+func TestProcess(t *testing.T) {
+	content := "gm @" + aliceNpub + " see nostr:" + nevent + " #Nostr #42 https://example.com/#frag mail@example.com"
+	text, tags, err := Process(content, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedText := "gm nostr:" + aliceNpub + " see nostr:" + nevent + " #Nostr #42 https://example.com/#frag mail@example.com"
+	if text != expectedText {
+		t.Fatalf("unexpected content %q", text)
+	}
+	expectedTags := nostrlib.Tags{
+		{"p", alice},
+		{"q", "45326f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751ac194", "wss://banana.com", "7fa56f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751abb88"},
+		{"t", "nostr"},
+	}
+	if !reflect.DeepEqual(tags, expectedTags) {
+		t.Fatalf("expected %v got %v", expectedTags, tags)
+	}
+}
+
+func TestProcessKeepsOverlappingHashtags(t *testing.T) {
+	_, tags, err := Process("hello #nostrdev #nostr #NostrDev", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := nostrlib.Tags{{"t", "nostrdev"}, {"t", "nostr"}}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Fatalf("expected %v got %v", expected, tags)
+	}
+}
+
+func TestProcessResolvesNIP05(t *testing.T) {
+	resolve := func(identifier string) (string, error) {
+		if identifier == "bob@example.com" {
+			return bob, nil
+		}
+		return "", errors.New("unknown")
+	}
+
+	text, tags, err := Process("hi @bob@example.com and @"+aliceNpub+" @"+aliceNpub, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "hi nostr:"+bobNpub+" and nostr:"+aliceNpub+" nostr:"+aliceNpub {
+		t.Fatalf("unexpected content %q", text)
+	}
+	if !reflect.DeepEqual(tags, nostrlib.Tags{{"p", bob}, {"p", alice}}) {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+
+	if _, _, err := Process("hi @carol@example.com", resolve); err == nil {
+		t.Fatal("expected unresolvable handle to fail")
+	}
+	if text, _, _ := Process("hi @carol@example.com", nil); text != "hi @carol@example.com" {
+		t.Fatalf("handles should be left alone without a resolver, got %q", text)
+	}
+}
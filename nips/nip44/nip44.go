@@ -0,0 +1,129 @@
+package nip44
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+
+	"github.com/nbd-wtf/go-nostr/nip04"
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/hkdf"
+)
+
+// version 2 payloads; the go-nostr release this module uses only ships NIP-04.
+const version = 2
+
+var ErrInvalidPayload = errors.New("invalid nip44 payload")
+
+// ConversationKey derives the key shared by sk and pubKey (both hex). It is
+// the same in both directions, so encrypting to yourself uses your own pubkey.
+func ConversationKey(pubKey, sk string) ([]byte, error) {
+	shared, err := nip04.ComputeSharedSecret(pubKey, sk)
+	if err != nil {
+		return nil, err
+	}
+	return hkdf.Extract(sha256.New, shared, []byte("nip44-v2")), nil
+}
+
+func Encrypt(plaintext string, conversationKey []byte) (string, error) {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return encrypt(plaintext, conversationKey, nonce)
+}
+
+func encrypt(plaintext string, conversationKey, nonce []byte) (string, error) {
+	if len(plaintext) < 1 || len(plaintext) > 65535 {
+		return "", fmt.Errorf("nip44 plaintext must be 1 to 65535 bytes, got %d", len(plaintext))
+	}
+	chachaKey, chachaNonce, hmacKey, err := messageKeys(conversationKey, nonce)
+	if err != nil {
+		return "", err
+	}
+
+	padded := make([]byte, 2+paddedLen(len(plaintext)))
+	binary.BigEndian.PutUint16(padded, uint16(len(plaintext)))
+	copy(padded[2:], plaintext)
+
+	cipher, err := chacha20.NewUnauthenticatedCipher(chachaKey, chachaNonce)
+	if err != nil {
+		return "", err
+	}
+	cipher.XORKeyStream(padded, padded)
+
+	payload := append([]byte{version}, nonce...)
+	payload = append(payload, padded...)
+	payload = append(payload, mac(hmacKey, nonce, padded)...)
+	return base64.StdEncoding.EncodeToString(payload), nil
+}
+
+func Decrypt(payload string, conversationKey []byte) (string, error) {
+	if len(payload) < 132 || len(payload) > 87472 || payload[0] == '#' {
+		return "", ErrInvalidPayload
+	}
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil || len(data) < 99 || data[0] != version {
+		return "", ErrInvalidPayload
+	}
+	nonce, ciphertext, sum := data[1:33], data[33:len(data)-32], data[len(data)-32:]
+
+	chachaKey, chachaNonce, hmacKey, err := messageKeys(conversationKey, nonce)
+	if err != nil {
+		return "", err
+	}
+	if !hmac.Equal(sum, mac(hmacKey, nonce, ciphertext)) {
+		return "", errors.New("nip44 payload failed authentication")
+	}
+
+	padded := make([]byte, len(ciphertext))
+	cipher, err := chacha20.NewUnauthenticatedCipher(chachaKey, chachaNonce)
+	if err != nil {
+		return "", err
+	}
+	cipher.XORKeyStream(padded, ciphertext)
+
+	length := int(binary.BigEndian.Uint16(padded))
+	if length < 1 || len(padded) != 2+paddedLen(length) {
+		return "", ErrInvalidPayload
+	}
+	return string(padded[2 : 2+length]), nil
+}
+
+func messageKeys(conversationKey, nonce []byte) ([]byte, []byte, []byte, error) {
+	if len(conversationKey) != 32 || len(nonce) != 32 {
+		return nil, nil, nil, errors.New("nip44 keys and nonces are 32 bytes")
+	}
+	keys := make([]byte, 76)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, conversationKey, nonce), keys); err != nil {
+		return nil, nil, nil, err
+	}
+	return keys[:32], keys[32:44], keys[44:], nil
+}
+
+func mac(key, nonce, ciphertext []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(nonce)
+	h.Write(ciphertext)
+	return h.Sum(nil)
+}
+
+// paddedLen rounds up to 32 bytes for short messages and to an eighth of the
+// next power of two above 256 bytes, so lengths leak as little as possible.
+func paddedLen(length int) int {
+	if length <= 32 {
+		return 32
+	}
+	nextPower := 1 << bits.Len(uint(length-1))
+	chunk := 32
+	if nextPower > 256 {
+		chunk = nextPower / 8
+	}
+	return chunk * ((length-1)/chunk + 1)
+}
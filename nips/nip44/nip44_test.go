@@ -0,0 +1,65 @@
+package nip44
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+)
+
+// Vectors from the NIP-44 specification.
+func TestEncryptVector(t *testing.T) {
+	sk1 := strings.Repeat("0", 63) + "1"
+	sk2 := strings.Repeat("0", 63) + "2"
+	pub2, _ := nostrlib.GetPublicKey(sk2)
+
+	key, err := ConversationKey(pub2, sk1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(key); got != "c41c775356fd92eadc63ff5a0dc1da211b268cbea22316767095b2871ea1412d" {
+		t.Fatalf("unexpected conversation key %s", got)
+	}
+
+	nonce, _ := hex.DecodeString(strings.Repeat("0", 63) + "1")
+	payload, err := encrypt("a", key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "AgAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABee0G5VSK0/9YypIObAtDKfYEAjD35uVkHyB0F4DwrcNaCXlCWZKaArsGrY6M9wnuTMxWfp1RTN9Xga8no+kF5Vsb"
+	if payload != expected {
+		t.Fatalf("unexpected payload %s", payload)
+	}
+
+	pub1, _ := nostrlib.GetPublicKey(sk1)
+	reverse, _ := ConversationKey(pub1, sk2)
+	plaintext, err := Decrypt(payload, reverse)
+	if err != nil || plaintext != "a" {
+		t.Fatalf("expected to decrypt \"a\", got %q (%v)", plaintext, err)
+	}
+}
+
+func TestPaddedLen(t *testing.T) {
+	cases := map[int]int{1: 32, 32: 32, 33: 64, 37: 64, 64: 64, 65: 96, 100: 128, 256: 256, 257: 320, 320: 320, 383: 384, 1000: 1024, 65535: 65536}
+	for length, expected := range cases {
+		if got := paddedLen(length); got != expected {
+			t.Fatalf("paddedLen(%d) = %d, expected %d", length, got, expected)
+		}
+	}
+}
+
+func TestDecryptRejectsTampering(t *testing.T) {
+	sk := nostrlib.GeneratePrivateKey()
+	pub, _ := nostrlib.GetPublicKey(sk)
+	key, _ := ConversationKey(pub, sk)
+	payload, err := Encrypt(`[["p","secret"]]`, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := []byte(payload)
+	tampered[50] ^= 1
+	if _, err := Decrypt(string(tampered), key); err == nil {
+		t.Fatalf("expected tampered payload to be rejected")
+	}
+}
@@ -0,0 +1,242 @@
+package nip51
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip04"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip01"
+	"nostr-cli/nips/nip44"
+	nostrkeys "nostr-cli/nostr"
+)
+
+const (
+	KindMuteList  = 10000
+	KindPinList   = 10001
+	KindBookmarks = 10003
+	KindFollowSet = 30000
+)
+
+var ErrNotFound = errors.New("list not found on configured relays")
+
+// Spec describes one of the standard lists and the tags its items use.
+type Spec struct {
+	Name  string
+	Title string
+	Kind  int
+	Items []string
+}
+
+var Specs = []Spec{
+	{Name: "mute", Title: "Mute list", Kind: KindMuteList, Items: []string{"p", "t", "word", "e"}},
+	{Name: "pins", Title: "Pinned notes", Kind: KindPinList, Items: []string{"e"}},
+	{Name: "bookmarks", Title: "Bookmarks", Kind: KindBookmarks, Items: []string{"e", "a"}},
+	{Name: "follow-set", Title: "Follow set", Kind: KindFollowSet, Items: []string{"p"}},
+}
+
+func LookupSpec(name string) (Spec, bool) {
+	for _, spec := range Specs {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return Spec{}, false
+}
+
+func (s Spec) Accepts(tagName string) bool {
+	for _, item := range s.Items {
+		if item == tagName {
+			return true
+		}
+	}
+	return false
+}
+
+// metadataTags describe a list rather than being items in it.
+var metadataTags = map[string]bool{"d": true, "title": true, "image": true, "description": true, "alt": true}
+
+// List is a decoded list: Tags holds the public tags as published, including
+// d and title, and Private the items encrypted to the author in content.
+type List struct {
+	Kind     int
+	Tags     nostrlib.Tags
+	Private  nostrlib.Tags
+	Previous *nostrlib.Event
+}
+
+func FetchList(ctx context.Context, relays []string, pubKey string, kind int, identifier string, auth *relay.Auth) (*nostrlib.Event, error) {
+	filter := nostrlib.Filter{Kinds: []int{kind}, Authors: []string{pubKey}}
+	if relay.IsAddressable(kind) {
+		filter.Tags = nostrlib.TagMap{"d": []string{identifier}}
+	}
+	ev, err := relay.FetchLatest(ctx, relays, filter, auth)
+	if err != nil {
+		if errors.Is(err, relay.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return ev, nil
+}
+
+// FetchSets returns the newest version of every set of kind by pubKey.
+func FetchSets(ctx context.Context, relays []string, pubKey string, kind int, auth *relay.Auth) ([]*nostrlib.Event, error) {
+	events, err := relay.QueryRelays(ctx, relays, nostrlib.Filter{Kinds: []int{kind}, Authors: []string{pubKey}}, auth)
+	if err != nil {
+		return nil, err
+	}
+	var sets []*nostrlib.Event
+	for _, ev := range relay.Newest(events) {
+		if ok, _ := ev.CheckSignature(); ok {
+			sets = append(sets, ev)
+		}
+	}
+	return sets, nil
+}
+
+// Open decodes ev, or starts an empty list when ev is nil. Private items are
+// decrypted with sk, accepting NIP-44 as well as the older NIP-04 payloads.
+func Open(kind int, identifier string, ev *nostrlib.Event, sk string) (*List, error) {
+	list := &List{Kind: kind, Previous: ev}
+	if ev == nil {
+		if relay.IsAddressable(kind) {
+			list.Tags = nostrlib.Tags{{"d", identifier}}
+		}
+		return list, nil
+	}
+	list.Tags = append(nostrlib.Tags{}, ev.Tags...)
+	if strings.TrimSpace(ev.Content) == "" {
+		return list, nil
+	}
+
+	plaintext, err := decryptToSelf(ev.Content, ev.PubKey, sk)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting private items: %w", err)
+	}
+	if err := json.Unmarshal([]byte(plaintext), &list.Private); err != nil {
+		return nil, fmt.Errorf("parsing private items: %w", err)
+	}
+	return list, nil
+}
+
+func decryptToSelf(content, pubKey, sk string) (string, error) {
+	if strings.Contains(content, "?iv=") {
+		secret, err := nip04.ComputeSharedSecret(pubKey, sk)
+		if err != nil {
+			return "", err
+		}
+		return nip04.Decrypt(content, secret)
+	}
+	key, err := nip44.ConversationKey(pubKey, sk)
+	if err != nil {
+		return "", err
+	}
+	return nip44.Decrypt(content, key)
+}
+
+func (l *List) Title() string {
+	if tag := l.Tags.GetFirst([]string{"title", ""}); tag != nil {
+		return tag.Value()
+	}
+	return ""
+}
+
+func (l *List) SetTitle(title string) {
+	var tags nostrlib.Tags
+	for _, tag := range l.Tags {
+		if len(tag) > 0 && tag[0] == "title" {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	l.Tags = append(tags, nostrlib.Tag{"title", title})
+}
+
+// Items returns the public items, leaving out tags such as d and title.
+func (l *List) Items() nostrlib.Tags {
+	var items nostrlib.Tags
+	for _, tag := range l.Tags {
+		if len(tag) >= 2 && !metadataTags[tag[0]] {
+			items = append(items, tag)
+		}
+	}
+	return items
+}
+
+func (l *List) Len() int {
+	return len(l.Items()) + len(l.Private)
+}
+
+// Add appends item publicly or privately unless the list already has it.
+func (l *List) Add(item nostrlib.Tag, private bool) bool {
+	if indexOf(l.Tags, item) >= 0 || indexOf(l.Private, item) >= 0 {
+		return false
+	}
+	if private {
+		l.Private = append(l.Private, item)
+	} else {
+		l.Tags = append(l.Tags, item)
+	}
+	return true
+}
+
+// Remove drops item, matched by tag name and value, from both parts.
+func (l *List) Remove(item nostrlib.Tag) bool {
+	removed := false
+	for _, tags := range []*nostrlib.Tags{&l.Tags, &l.Private} {
+		for i := indexOf(*tags, item); i >= 0; i = indexOf(*tags, item) {
+			*tags = append((*tags)[:i:i], (*tags)[i+1:]...)
+			removed = true
+		}
+	}
+	return removed
+}
+
+func indexOf(tags nostrlib.Tags, item nostrlib.Tag) int {
+	for i, tag := range tags {
+		if len(tag) >= 2 && len(item) >= 2 && tag[0] == item[0] && strings.EqualFold(tag[1], item[1]) && !metadataTags[tag[0]] {
+			return i
+		}
+	}
+	return -1
+}
+
+// Event encodes the list for signing, encrypting the private items to the
+// author with NIP-44.
+func (l *List) Event(pubKey, sk string) (*nostrlib.Event, error) {
+	ev := &nostrlib.Event{Kind: l.Kind, CreatedAt: nip01.After(l.Previous), Tags: append(nostrlib.Tags{}, l.Tags...)}
+	if len(l.Private) == 0 {
+		return ev, nil
+	}
+
+	plaintext, err := json.Marshal(l.Private)
+	if err != nil {
+		return nil, err
+	}
+	key, err := nip44.ConversationKey(pubKey, sk)
+	if err != nil {
+		return nil, err
+	}
+	if ev.Content, err = nip44.Encrypt(string(plaintext), key); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+func PublishList(ctx context.Context, profile *nostrkeys.Profile, sk string, list *List, policy relay.Policy) (*nostrlib.Event, []relay.PublishResult, error) {
+	ev, err := list.Event(profile.PublicKey, sk)
+	if err != nil {
+		return nil, nil, err
+	}
+	results, err := nip01.PublishEvent(ctx, profile, sk, ev, nil, policy)
+	if results == nil && err != nil {
+		return nil, nil, err
+	}
+	return ev, results, err
+}
@@ -0,0 +1,82 @@
+package nip51
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+	"github.com/nbd-wtf/go-nostr/nip04"
+)
+
+func TestListRoundTrip(t *testing.T) {
+	sk := nostrlib.GeneratePrivateKey()
+	pubKey, _ := nostrlib.GetPublicKey(sk)
+	alice := strings.Repeat("a", 64)
+	bob := strings.Repeat("b", 64)
+
+	list, err := Open(KindFollowSet, "rust", nil, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list.SetTitle("Rust people")
+	if !list.Add(nostrlib.Tag{"p", alice}, false) || !list.Add(nostrlib.Tag{"p", bob}, true) {
+		t.Fatalf("expected both items to be added")
+	}
+	if list.Add(nostrlib.Tag{"p", strings.ToUpper(bob)}, false) {
+		t.Fatalf("expected a duplicate item to be ignored")
+	}
+
+	ev, err := list.Event(pubKey, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev.PubKey = pubKey
+	if strings.Contains(ev.Content, bob) {
+		t.Fatalf("private item leaked into content %q", ev.Content)
+	}
+	expected := nostrlib.Tags{{"d", "rust"}, {"title", "Rust people"}, {"p", alice}}
+	if !reflect.DeepEqual(ev.Tags, expected) {
+		t.Fatalf("expected public tags %v, got %v", expected, ev.Tags)
+	}
+
+	reopened, err := Open(KindFollowSet, "rust", ev, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(reopened.Private, nostrlib.Tags{{"p", bob}}) || reopened.Title() != "Rust people" || reopened.Len() != 2 {
+		t.Fatalf("unexpected reopened list %+v", reopened)
+	}
+	if !reopened.Remove(nostrlib.Tag{"p", bob}) || reopened.Len() != 1 || reopened.Remove(nostrlib.Tag{"p", bob}) {
+		t.Fatalf("expected bob to be removed exactly once")
+	}
+
+	next, err := reopened.Event(pubKey, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.CreatedAt <= ev.CreatedAt || next.Content != "" {
+		t.Fatalf("expected a newer list without private content, got %d %q", next.CreatedAt, next.Content)
+	}
+}
+
+func TestOpenLegacyNIP04(t *testing.T) {
+	sk := nostrlib.GeneratePrivateKey()
+	pubKey, _ := nostrlib.GetPublicKey(sk)
+	secret, _ := nip04.ComputeSharedSecret(pubKey, sk)
+	private, _ := json.Marshal(nostrlib.Tags{{"word", "spoilers"}})
+	content, err := nip04.Encrypt(string(private), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ev := &nostrlib.Event{Kind: KindMuteList, PubKey: pubKey, Content: content, Tags: nostrlib.Tags{{"t", "spam"}}}
+	list, err := Open(KindMuteList, "", ev, sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(list.Private, nostrlib.Tags{{"word", "spoilers"}}) || len(list.Items()) != 1 {
+		t.Fatalf("unexpected list %+v", list)
+	}
+}
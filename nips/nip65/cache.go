@@ -0,0 +1,136 @@
+package nip65
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	"nostr-cli/internal/fileutil"
+	"nostr-cli/internal/relay"
+	nostrkeys "nostr-cli/nostr"
+)
+
+// CacheTTL is how long a discovered relay list, or the absence of one, is
+// trusted before it is fetched again.
+const CacheTTL = 6 * time.Hour
+
+type cachedList struct {
+	Entries   []RelayEntry `json:"relays"`
+	FetchedAt time.Time    `json:"fetched_at"`
+}
+
+func GetCachePath() string {
+	return filepath.Join(filepath.Dir(nostrkeys.GetConfigPath()), "relay_lists.json")
+}
+
+// Discover returns the relay lists of pubKeys, keyed by public key. Lists are
+// taken from the cache while they are fresh and otherwise fetched from relays
+// plus IndexerRelays. Authors without a list are left out of the result; when
+// fetching fails, expired cache entries are returned along with the error.
+func Discover(ctx context.Context, relays []string, pubKeys []string, auth *relay.Auth) (map[string]*RelayList, error) {
+	cache := loadCache()
+	now := time.Now()
+	lists := make(map[string]*RelayList)
+	var missing []string
+	for _, pubKey := range pubKeys {
+		cached, ok := cache[pubKey]
+		if ok && now.Sub(cached.FetchedAt) < CacheTTL {
+			if len(cached.Entries) > 0 {
+				lists[pubKey] = &RelayList{Entries: cached.Entries}
+			}
+			continue
+		}
+		if !containsString(missing, pubKey) {
+			missing = append(missing, pubKey)
+		}
+	}
+	if len(missing) == 0 {
+		return lists, nil
+	}
+
+	queryRelays := append(append([]string{}, relays...), IndexerRelays...)
+	events, err := relay.QueryRelays(ctx, queryRelays, nostrlib.Filter{Kinds: []int{KindRelayList}, Authors: missing}, auth)
+	if err != nil {
+		for _, pubKey := range missing {
+			if cached, ok := cache[pubKey]; ok && len(cached.Entries) > 0 {
+				lists[pubKey] = &RelayList{Entries: cached.Entries}
+			}
+		}
+		return lists, err
+	}
+
+	fetched := make(map[string]cachedList)
+	for _, pubKey := range missing {
+		fetched[pubKey] = cachedList{FetchedAt: now}
+	}
+	var valid []*nostrlib.Event
+	for _, ev := range events {
+		if ok, _ := ev.CheckSignature(); ok && ev.Kind == KindRelayList {
+			valid = append(valid, ev)
+		}
+	}
+	for _, ev := range relay.Newest(valid) {
+		if containsString(missing, ev.PubKey) {
+			fetched[ev.PubKey] = cachedList{Entries: ParseRelayList(ev.Tags).Entries, FetchedAt: now}
+		}
+	}
+	for _, pubKey := range missing {
+		if entries := fetched[pubKey].Entries; len(entries) > 0 {
+			lists[pubKey] = &RelayList{Entries: entries}
+		}
+	}
+	return lists, saveCache(fetched, now)
+}
+
+func loadCache() map[string]cachedList {
+	cache := make(map[string]cachedList)
+	data, err := os.ReadFile(GetCachePath())
+	if err != nil {
+		return cache
+	}
+	// a damaged cache is simply rebuilt
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(map[string]cachedList)
+	}
+	return cache
+}
+
+// saveCache merges fetched into the cache as it is on disk, so lists another
+// command fetched meanwhile are kept, and drops expired entries.
+func saveCache(fetched map[string]cachedList, now time.Time) error {
+	cachePath := GetCachePath()
+	unlock, err := fileutil.Lock(cachePath + ".lock")
+	if err != nil {
+		return fmt.Errorf("locking relay list cache: %w", err)
+	}
+	defer unlock()
+
+	cache := loadCache()
+	for pubKey, cached := range fetched {
+		cache[pubKey] = cached
+	}
+	for pubKey, cached := range cache {
+		if now.Sub(cached.FetchedAt) >= CacheTTL {
+			delete(cache, pubKey)
+		}
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAtomic(cachePath, data)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
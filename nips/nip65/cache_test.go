@@ -0,0 +1,87 @@
+package nip65
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiscoverUsesFreshCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+	entries := []RelayEntry{{URL: "wss://outbox.example", Write: true}}
+	err := saveCache(map[string]cachedList{
+		"alice": {Entries: entries, FetchedAt: now},
+		"bob":   {FetchedAt: now},
+	}, now)
+	if err != nil {
+		t.Fatalf("save cache: %v", err)
+	}
+
+	// no relays are given, so anything not served from the cache would fail
+	lists, err := Discover(context.Background(), nil, []string{"alice", "bob"}, nil)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	if len(lists) != 1 || !reflect.DeepEqual(lists["alice"].Entries, entries) {
+		t.Fatalf("unexpected lists %+v", lists)
+	}
+}
+
+func TestDiscoverFallsBackToExpiredCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	fetched := time.Now().Add(-2 * CacheTTL)
+	entries := []RelayEntry{{URL: "wss://outbox.example", Read: true, Write: true}}
+	cache := map[string]cachedList{"alice": {Entries: entries, FetchedAt: fetched}}
+	if err := saveCache(cache, fetched); err != nil {
+		t.Fatalf("save cache: %v", err)
+	}
+
+	saved := IndexerRelays
+	IndexerRelays = nil
+	defer func() { IndexerRelays = saved }()
+	lists, err := Discover(context.Background(), nil, []string{"alice"}, nil)
+	if err == nil {
+		t.Fatalf("expected an error without relays to query")
+	}
+	if lists["alice"] == nil || !reflect.DeepEqual(lists["alice"].Entries, entries) {
+		t.Fatalf("expected the expired entry, got %+v", lists)
+	}
+}
+
+func TestSaveCacheKeepsConcurrentWrites(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Now()
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(pubKey string) {
+			defer wg.Done()
+			errs <- saveCache(map[string]cachedList{pubKey: {FetchedAt: now}}, now)
+		}(fmt.Sprintf("pubkey%d", i))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("save cache: %v", err)
+		}
+	}
+
+	if cache := loadCache(); len(cache) != 10 {
+		t.Fatalf("expected every write to be kept, got %d entries", len(cache))
+	}
+	files, err := os.ReadDir(filepath.Dir(GetCachePath()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("expected only the cache file, got %v", files)
+	}
+}
@@ -0,0 +1,164 @@
+package nip65
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	"nostr-cli/internal/relay"
+	"nostr-cli/nips/nip01"
+	nostrkeys "nostr-cli/nostr"
+)
+
+const KindRelayList = 10002
+
+var ErrNotFound = errors.New("no relay list metadata was found on the queried relays")
+
+// IndexerRelays collect everyone's relay lists, so publishing there lets
+// clients that share none of our relays find us.
+var IndexerRelays = []string{
+	"wss://purplepag.es",
+	"wss://user.kindpag.es",
+	"wss://indexer.coracle.social",
+}
+
+type RelayEntry struct {
+	URL   string `json:"url"`
+	Read  bool   `json:"read"`
+	Write bool   `json:"write"`
+}
+
+type RelayList struct {
+	Event   *nostrlib.Event `json:"-"`
+	Entries []RelayEntry    `json:"relays"`
+}
+
+func FetchRelayList(ctx context.Context, relays []string, pubKey string, auth *relay.Auth) (*RelayList, error) {
+	if pubKey == "" {
+		return nil, errors.New("a public key is required")
+	}
+	ev, err := relay.FetchLatest(ctx, relays, nostrlib.Filter{Kinds: []int{KindRelayList}, Authors: []string{pubKey}}, auth)
+	if err != nil {
+		if errors.Is(err, relay.ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	list := ParseRelayList(ev.Tags)
+	list.Event = ev
+	return list, nil
+}
+
+func ParseRelayList(tags nostrlib.Tags) *RelayList {
+	list := &RelayList{}
+	seen := make(map[string]int)
+	for _, tag := range tags {
+		if len(tag) < 2 || tag[0] != "r" {
+			continue
+		}
+		url := strings.TrimRight(strings.TrimSpace(tag[1]), "/")
+		if url == "" {
+			continue
+		}
+		entry := RelayEntry{URL: url, Read: true, Write: true}
+		if len(tag) >= 3 {
+			switch strings.ToLower(strings.TrimSpace(tag[2])) {
+			case "read":
+				entry.Write = false
+			case "write":
+				entry.Read = false
+			}
+		}
+		key := strings.ToLower(url)
+		if index, ok := seen[key]; ok {
+			list.Entries[index].Read = list.Entries[index].Read || entry.Read
+			list.Entries[index].Write = list.Entries[index].Write || entry.Write
+			continue
+		}
+		seen[key] = len(list.Entries)
+		list.Entries = append(list.Entries, entry)
+	}
+	return list
+}
+
+func (l *RelayList) ReadRelays() []string {
+	var urls []string
+	for _, entry := range l.Entries {
+		if entry.Read {
+			urls = append(urls, entry.URL)
+		}
+	}
+	return urls
+}
+
+func (l *RelayList) WriteRelays() []string {
+	var urls []string
+	for _, entry := range l.Entries {
+		if entry.Write {
+			urls = append(urls, entry.URL)
+		}
+	}
+	return urls
+}
+
+// FromProfile builds the relay list described by the profile's relays and
+// their read/write markers.
+func FromProfile(profile *nostrkeys.Profile) *RelayList {
+	list := &RelayList{}
+	for _, url := range profile.Relays {
+		url = strings.TrimRight(strings.TrimSpace(url), "/")
+		if url == "" {
+			continue
+		}
+		marker := profile.RelayMarker(url)
+		list.Entries = append(list.Entries, RelayEntry{URL: url, Read: marker != nostrkeys.MarkerWrite, Write: marker != nostrkeys.MarkerRead})
+	}
+	return list
+}
+
+// ApplyTo replaces the profile's relays and markers with the list.
+func (l *RelayList) ApplyTo(profile *nostrkeys.Profile) {
+	profile.Relays = nil
+	profile.ReadOnlyRelays = nil
+	profile.WriteOnlyRelays = nil
+	for _, entry := range l.Entries {
+		profile.Relays = append(profile.Relays, entry.URL)
+		profile.SetRelayMarker(entry.URL, entry.Marker())
+	}
+}
+
+// Marker returns the r tag marker for the entry, "" when it is used both ways.
+func (e RelayEntry) Marker() string {
+	switch {
+	case e.Read && !e.Write:
+		return nostrkeys.MarkerRead
+	case e.Write && !e.Read:
+		return nostrkeys.MarkerWrite
+	}
+	return ""
+}
+
+func (l *RelayList) Tags() nostrlib.Tags {
+	tags := nostrlib.Tags{}
+	for _, entry := range l.Entries {
+		tag := nostrlib.Tag{"r", entry.URL}
+		if marker := entry.Marker(); marker != "" {
+			tag = append(tag, marker)
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// PublishRelayList replaces previous (nil if none was found) with the list,
+// sending it to the profile's relays plus extra ones such as IndexerRelays.
+func PublishRelayList(ctx context.Context, profile *nostrkeys.Profile, sk string, list *RelayList, previous *nostrlib.Event, extra []string, policy relay.Policy) (*nostrlib.Event, []relay.PublishResult, error) {
+	ev := &nostrlib.Event{Kind: KindRelayList, CreatedAt: nip01.After(previous), Tags: list.Tags()}
+	results, err := nip01.PublishEvent(ctx, profile, sk, ev, extra, policy)
+	if results == nil && err != nil {
+		return nil, nil, err
+	}
+	return ev, results, err
+}
@@ -0,0 +1,37 @@
+package nip65
+
+import (
+	"reflect"
+	"testing"
+
+	nostrlib "github.com/nbd-wtf/go-nostr"
+
+	nostrkeys "nostr-cli/nostr"
+)
+
+func TestRelayListRoundTrip(t *testing.T) {
+	tags := nostrlib.Tags{
+		{"r", "wss://both.example"},
+		{"r", "wss://inbox.example/", "read"},
+		{"r", "wss://outbox.example", "write"},
+	}
+	list := ParseRelayList(tags)
+
+	profile := &nostrkeys.Profile{}
+	list.ApplyTo(profile)
+	if !reflect.DeepEqual(profile.Relays, []string{"wss://both.example", "wss://inbox.example", "wss://outbox.example"}) {
+		t.Fatalf("unexpected relays %v", profile.Relays)
+	}
+	if !reflect.DeepEqual(profile.ReadOnlyRelays, []string{"wss://inbox.example"}) || !reflect.DeepEqual(profile.WriteOnlyRelays, []string{"wss://outbox.example"}) {
+		t.Fatalf("unexpected markers read=%v write=%v", profile.ReadOnlyRelays, profile.WriteOnlyRelays)
+	}
+
+	expected := nostrlib.Tags{
+		{"r", "wss://both.example"},
+		{"r", "wss://inbox.example", "read"},
+		{"r", "wss://outbox.example", "write"},
+	}
+	if got := FromProfile(profile).Tags(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
@@ -27,11 +27,16 @@ type Config struct {
 	Profiles       map[string]*Profile `json:"profiles"`
 }
 
+// Profile.Relays lists every relay. ReadOnlyRelays and WriteOnlyRelays hold
+// the NIP-65 markers for some of them; unmarked relays are used both ways.
 type Profile struct {
-	Relays    []string `json:"relays"`
-	PrivKey   string   `json:"encrypted_private_key"`
-	Salt      string   `json:"salt"`
-	PublicKey string   `json:"public_key"`
+	Relays          []string `json:"relays"`
+	ReadOnlyRelays  []string `json:"read_only_relays,omitempty"`
+	WriteOnlyRelays []string `json:"write_only_relays,omitempty"`
+	AuthRelays      []string `json:"auth_relays,omitempty"`
+	PrivKey         string   `json:"encrypted_private_key"`
+	Salt            string   `json:"salt"`
+	PublicKey       string   `json:"public_key"`
 }
 
 type legacyConfig struct {
@@ -242,26 +247,30 @@ func RunSetup(alias string) error {
 }
 
 func nsecToHex(nsec string) (string, error) {
-	hrp, data, err := bech32.Decode(nsec)
+	ref, err := DecodeReference(nsec)
 	if err != nil {
 		return "", fmt.Errorf("invalid nsec format: %v", err)
 	}
-	if hrp != "nsec" {
-		return "", fmt.Errorf("invalid prefix: expected nsec, got %s", hrp)
-	}
-
-	converted, err := bech32.ConvertBits(data, 5, 8, false)
-	if err != nil {
-		return "", fmt.Errorf("failed to convert bits: %v", err)
+	if ref.Type != "nsec" {
+		return "", fmt.Errorf("invalid prefix: expected nsec, got %s", ref.Type)
 	}
-
-	return hex.EncodeToString(converted), nil
+	return ref.SecretKey, nil
 }
 
 func readPassword(prompt string) (string, error) {
-	fmt.Print(prompt)
-	bytePassword, err := term.ReadPassword(int(syscall.Stdin))
-	fmt.Println()
+	fd := int(syscall.Stdin)
+	if !term.IsTerminal(fd) {
+		// stdin carries piped content, so ask on the controlling terminal
+		tty, err := os.Open("/dev/tty")
+		if err != nil {
+			return "", fmt.Errorf("no terminal available for the password prompt: %w", err)
+		}
+		defer tty.Close()
+		fd = int(tty.Fd())
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	bytePassword, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
 	if err != nil {
 		return "", err
 	}
@@ -309,17 +318,20 @@ func RunSetupWithKey(alias, sk string) error {
 	}
 
 	cfg.ensureProfiles()
-	relays := DefaultRelays()
-	if existing, ok := cfg.Profiles[alias]; ok && len(existing.Relays) > 0 {
-		relays = append([]string{}, existing.Relays...)
-	}
-
 	profile := &Profile{
-		Relays:    relays,
+		Relays:    DefaultRelays(),
 		PrivKey:   encryptedKey,
 		Salt:      hex.EncodeToString(salt),
 		PublicKey: pk,
 	}
+	if existing, ok := cfg.Profiles[alias]; ok {
+		if len(existing.Relays) > 0 {
+			profile.Relays = append([]string{}, existing.Relays...)
+			profile.ReadOnlyRelays = append([]string{}, existing.ReadOnlyRelays...)
+			profile.WriteOnlyRelays = append([]string{}, existing.WriteOnlyRelays...)
+		}
+		profile.AuthRelays = append([]string{}, existing.AuthRelays...)
+	}
 
 	cfg.Profiles[alias] = profile
 	cfg.CurrentProfile = alias
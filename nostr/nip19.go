@@ -0,0 +1,233 @@
+package nostr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+const (
+	tlvSpecial = 0
+	tlvRelay   = 1
+	tlvAuthor  = 2
+	tlvKind    = 3
+)
+
+// Reference is a decoded NIP-19 entity. Type is the bech32 prefix, or "hex"
+// for a bare 64 character hex string, which may be a key or an event id. Kind
+// is nil unless the entity carried one, which an naddr always does.
+type Reference struct {
+	Type       string   `json:"type"`
+	PubKey     string   `json:"pubkey,omitempty"`
+	SecretKey  string   `json:"secret_key,omitempty"`
+	EventID    string   `json:"event_id,omitempty"`
+	Identifier string   `json:"identifier,omitempty"`
+	Kind       *int     `json:"kind,omitempty"`
+	Relays     []string `json:"relays,omitempty"`
+}
+
+// DecodeReference accepts npub, nsec, note, nprofile, nevent and naddr
+// strings, optionally as NIP-21 "nostr:" URIs, as well as bare hex.
+func DecodeReference(value string) (*Reference, error) {
+	value = strings.TrimSpace(value)
+	uri := false
+	if len(value) > 6 && strings.EqualFold(value[:6], "nostr:") {
+		value = value[6:]
+		uri = true
+	}
+	if isHex32(value) {
+		if uri {
+			return nil, errors.New("nostr: URIs must use a bech32 entity, not hex")
+		}
+		return &Reference{Type: "hex", PubKey: strings.ToLower(value), EventID: strings.ToLower(value)}, nil
+	}
+
+	hrp, data, err := bech32.DecodeNoLimit(strings.ToLower(value))
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a hex value or NIP-19 identifier", value)
+	}
+	data, err = bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", hrp, err)
+	}
+
+	ref := &Reference{Type: hrp}
+	switch hrp {
+	case "npub", "nsec", "note":
+		if len(data) != 32 {
+			return nil, fmt.Errorf("%s must hold 32 bytes, got %d", hrp, len(data))
+		}
+		encoded := hex.EncodeToString(data)
+		switch hrp {
+		case "npub":
+			ref.PubKey = encoded
+		case "nsec":
+			if uri {
+				return nil, errors.New("nostr: URIs must not contain an nsec")
+			}
+			ref.SecretKey = encoded
+		case "note":
+			ref.EventID = encoded
+		}
+	case "nprofile", "nevent", "naddr":
+		if err := ref.decodeTLV(data); err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", hrp, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported NIP-19 prefix %q", hrp)
+	}
+	return ref, nil
+}
+
+func (ref *Reference) decodeTLV(data []byte) error {
+	special := false
+	for len(data) > 0 {
+		if len(data) < 2 || len(data) < 2+int(data[1]) {
+			return errors.New("truncated TLV entry")
+		}
+		typ, value := data[0], data[2:2+int(data[1])]
+		data = data[2+len(value):]
+
+		switch typ {
+		case tlvSpecial:
+			special = true
+			switch ref.Type {
+			case "naddr":
+				ref.Identifier = string(value)
+			case "nprofile":
+				if len(value) != 32 {
+					return errors.New("public key must be 32 bytes")
+				}
+				ref.PubKey = hex.EncodeToString(value)
+			case "nevent":
+				if len(value) != 32 {
+					return errors.New("event id must be 32 bytes")
+				}
+				ref.EventID = hex.EncodeToString(value)
+			}
+		case tlvRelay:
+			ref.Relays = append(ref.Relays, string(value))
+		case tlvAuthor:
+			if len(value) != 32 {
+				return errors.New("author must be 32 bytes")
+			}
+			ref.PubKey = hex.EncodeToString(value)
+		case tlvKind:
+			if len(value) != 4 {
+				return errors.New("kind must be 4 bytes")
+			}
+			kind := int(binary.BigEndian.Uint32(value))
+			ref.Kind = &kind
+		}
+		// unknown TLV types are skipped as NIP-19 requires
+	}
+
+	if !special {
+		return errors.New("missing required entry")
+	}
+	if ref.Type == "naddr" && ref.PubKey == "" {
+		return errors.New("naddr is missing the author")
+	}
+	if ref.Type == "naddr" && ref.Kind == nil {
+		return errors.New("naddr is missing the kind")
+	}
+	return nil
+}
+
+func HexToNote(id string) (string, error) {
+	return hexToBech32("note", id)
+}
+
+func EncodeProfile(pubKey string, relays []string) (string, error) {
+	var buf bytes.Buffer
+	if err := writeHexTLV(&buf, tlvSpecial, pubKey); err != nil {
+		return "", err
+	}
+	writeRelayTLVs(&buf, relays)
+	return encodeTLV("nprofile", buf.Bytes())
+}
+
+// EncodeEvent builds an nevent; author and kind are optional hints and are
+// left out when empty or negative.
+func EncodeEvent(id string, relays []string, author string, kind int) (string, error) {
+	var buf bytes.Buffer
+	if err := writeHexTLV(&buf, tlvSpecial, id); err != nil {
+		return "", err
+	}
+	writeRelayTLVs(&buf, relays)
+	if author != "" {
+		if err := writeHexTLV(&buf, tlvAuthor, author); err != nil {
+			return "", err
+		}
+	}
+	if kind >= 0 {
+		writeKindTLV(&buf, kind)
+	}
+	return encodeTLV("nevent", buf.Bytes())
+}
+
+func EncodeAddress(pubKey string, kind int, identifier string, relays []string) (string, error) {
+	if len(identifier) > 255 {
+		return "", errors.New("identifier must be at most 255 bytes")
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(tlvSpecial)
+	buf.WriteByte(byte(len(identifier)))
+	buf.WriteString(identifier)
+	writeRelayTLVs(&buf, relays)
+	if err := writeHexTLV(&buf, tlvAuthor, pubKey); err != nil {
+		return "", err
+	}
+	writeKindTLV(&buf, kind)
+	return encodeTLV("naddr", buf.Bytes())
+}
+
+func writeHexTLV(buf *bytes.Buffer, typ byte, value string) error {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if !isHex32(value) {
+		return fmt.Errorf("%q is not 64 hex characters", value)
+	}
+	decoded, _ := hex.DecodeString(value)
+	buf.WriteByte(typ)
+	buf.WriteByte(32)
+	buf.Write(decoded)
+	return nil
+}
+
+func writeRelayTLVs(buf *bytes.Buffer, relays []string) {
+	for _, relay := range relays {
+		if relay == "" || len(relay) > 255 {
+			continue
+		}
+		buf.WriteByte(tlvRelay)
+		buf.WriteByte(byte(len(relay)))
+		buf.WriteString(relay)
+	}
+}
+
+func writeKindTLV(buf *bytes.Buffer, kind int) {
+	buf.WriteByte(tlvKind)
+	buf.WriteByte(4)
+	binary.Write(buf, binary.BigEndian, uint32(kind))
+}
+
+func encodeTLV(hrp string, data []byte) (string, error) {
+	converted, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32.Encode(hrp, converted)
+}
+
+func isHex32(value string) bool {
+	if len(value) != 64 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
@@ -0,0 +1,127 @@
+package nostr
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func kindOf(kind int) *int {
+	return &kind
+}
+
+func TestDecodeReference(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Reference
+	}{
+		{
+			"npub10elfcs4fr0l0r8af98jlmgdh9c8tcxjvz9qkw038js35mp4dma8qzvjptg",
+			Reference{Type: "npub", PubKey: "7e7e9c42a91bfef19fa929e5fda1b72e0ebc1a4c1141673e2794234d86addf4e"},
+		},
+		{
+			"nsec1vl029mgpspedva04g90vltkh6fvh240zqtv9k0t9af8935ke9laqsnlfe5",
+			Reference{Type: "nsec", SecretKey: "67dea2ed018072d675f5415ecfaed7d2597555e202d85b3d65ea4e58d2d92ffa"},
+		},
+		{
+			"nostr:nprofile1qqsrhuxx8l9ex335q7he0f09aej04zpazpl0ne2cgukyawd24mayt8gpp4mhxue69uhhytnc9e3k7mgpz4mhxue69uhkg6nzv9ejuumpv34kytnrdaksjlyr9p",
+			Reference{Type: "nprofile", PubKey: "3bf0c63fcb93463407af97a5e5ee64fa883d107ef9e558472c4eb9aaaefa459d", Relays: []string{"wss://r.x.com", "wss://djbas.sadkb.com"}},
+		},
+		{
+			"nevent1qqsy2vn0t45k92c78n2zfe6ccvqzhpn977cd3h8wnl579zxhw5dvr9qpzpmhxue69uhkyctwv9hxztnrdaksygrl54h466tz4v0re4pyuavvxqptsejl0vxcmnhfl60z3rth2x4m3q04ndyp",
+			Reference{Type: "nevent", EventID: "45326f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751ac194", PubKey: "7fa56f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751abb88", Relays: []string{"wss://banana.com"}},
+		},
+		{
+			"naddr1qqrxyctwv9hxzqfwwaehxw309aex2mrp0yhxummnw3ezuetcv9khqmr99ekhjer0d4skjm3wv4uxzmtsd3jjucm0d5q3vamnwvaz7tmwdaehgu3wvfskuctwvyhxxmmdqgsrhuxx8l9ex335q7he0f09aej04zpazpl0ne2cgukyawd24mayt8grqsqqqa28a3lkds",
+			Reference{Type: "naddr", PubKey: "3bf0c63fcb93463407af97a5e5ee64fa883d107ef9e558472c4eb9aaaefa459d", Identifier: "banana", Kind: kindOf(30023), Relays: []string{"wss://relay.nostr.example.mydomain.example.com", "wss://nostr.banana.com"}},
+		},
+	}
+
+	for _, test := range tests {
+		ref, err := DecodeReference(test.input)
+		if err != nil {
+			t.Fatalf("%s: %v", test.input, err)
+		}
+		if !reflect.DeepEqual(*ref, test.expected) {
+			t.Fatalf("%s:\nexpected %+v\ngot      %+v", test.input, test.expected, *ref)
+		}
+	}
+}
+
+func TestDecodeReferenceRejects(t *testing.T) {
+	for _, input := range []string{
+		"nostr:nsec1vl029mgpspedva04g90vltkh6fvh240zqtv9k0t9af8935ke9laqsnlfe5",
+		"npub10elfcs4fr0l0r8af98jlmgdh9c8tcxjvz9qkw038js35mp4dma8qzvjptx",
+		"lnurl1dp68gurn8ghj7",
+		"not a reference",
+	} {
+		if _, err := DecodeReference(input); err == nil {
+			t.Fatalf("expected %q to be rejected", input)
+		}
+	}
+}
+
+func TestEncodeReferences(t *testing.T) {
+	naddr, err := EncodeAddress("3bf0c63fcb93463407af97a5e5ee64fa883d107ef9e558472c4eb9aaaefa459d", 30023, "banana", []string{"wss://relay.nostr.example.mydomain.example.com", "wss://nostr.banana.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if naddr != "naddr1qqrxyctwv9hxzqfwwaehxw309aex2mrp0yhxummnw3ezuetcv9khqmr99ekhjer0d4skjm3wv4uxzmtsd3jjucm0d5q3vamnwvaz7tmwdaehgu3wvfskuctwvyhxxmmdqgsrhuxx8l9ex335q7he0f09aej04zpazpl0ne2cgukyawd24mayt8grqsqqqa28a3lkds" {
+		t.Fatalf("unexpected naddr %s", naddr)
+	}
+
+	nprofile, err := EncodeProfile("3bf0c63fcb93463407af97a5e5ee64fa883d107ef9e558472c4eb9aaaefa459d", []string{"wss://r.x.com", "wss://djbas.sadkb.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nprofile != "nprofile1qqsrhuxx8l9ex335q7he0f09aej04zpazpl0ne2cgukyawd24mayt8gpp4mhxue69uhhytnc9e3k7mgpz4mhxue69uhkg6nzv9ejuumpv34kytnrdaksjlyr9p" {
+		t.Fatalf("unexpected nprofile %s", nprofile)
+	}
+
+	nevent, err := EncodeEvent("45326f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751ac194", []string{"wss://banana.com"}, "7fa56f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751abb88", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref, err := DecodeReference(nevent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Reference{Type: "nevent", EventID: "45326f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751ac194", PubKey: "7fa56f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751abb88", Kind: kindOf(1), Relays: []string{"wss://banana.com"}}
+	if !reflect.DeepEqual(*ref, expected) {
+		t.Fatalf("round trip mismatch: %+v", *ref)
+	}
+}
+
+func TestReferenceJSONKeepsKindZero(t *testing.T) {
+	author := "3bf0c63fcb93463407af97a5e5ee64fa883d107ef9e558472c4eb9aaaefa459d"
+	nevent, err := EncodeEvent("45326f5d6962ab1e3cd424e758c3002b8665f7b0d8dcee9fe9e288d7751ac194", nil, author, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	naddr, err := EncodeAddress(author, 0, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, value := range []string{nevent, naddr} {
+		ref, err := DecodeReference(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), `"kind":0`) {
+			t.Fatalf("expected kind 0 in %s", data)
+		}
+	}
+
+	ref, err := DecodeReference("nostr:nprofile1qqsrhuxx8l9ex335q7he0f09aej04zpazpl0ne2cgukyawd24mayt8gpp4mhxue69uhhytnc9e3k7mgpz4mhxue69uhkg6nzv9ejuumpv34kytnrdaksjlyr9p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := json.Marshal(ref); strings.Contains(string(data), "kind") {
+		t.Fatalf("expected no kind for an nprofile, got %s", data)
+	}
+}
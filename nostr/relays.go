@@ -0,0 +1,74 @@
+package nostr
+
+import "strings"
+
+const (
+	MarkerRead  = "read"
+	MarkerWrite = "write"
+)
+
+// RelayMarker returns MarkerRead or MarkerWrite for a relay used in one
+// direction only, and "" for one used for both.
+func (p *Profile) RelayMarker(url string) string {
+	switch {
+	case containsRelay(p.ReadOnlyRelays, url):
+		return MarkerRead
+	case containsRelay(p.WriteOnlyRelays, url):
+		return MarkerWrite
+	}
+	return ""
+}
+
+func (p *Profile) SetRelayMarker(url, marker string) {
+	p.ReadOnlyRelays = withoutRelay(p.ReadOnlyRelays, url)
+	p.WriteOnlyRelays = withoutRelay(p.WriteOnlyRelays, url)
+	switch marker {
+	case MarkerRead:
+		p.ReadOnlyRelays = append(p.ReadOnlyRelays, url)
+	case MarkerWrite:
+		p.WriteOnlyRelays = append(p.WriteOnlyRelays, url)
+	}
+}
+
+// ReadRelays are the relays others should use to reach this profile (its
+// inbox); WriteRelays are where its own events are published (its outbox).
+func (p *Profile) ReadRelays() []string {
+	return p.relaysExcept(MarkerWrite)
+}
+
+func (p *Profile) WriteRelays() []string {
+	return p.relaysExcept(MarkerRead)
+}
+
+func (p *Profile) relaysExcept(marker string) []string {
+	var relays []string
+	for _, url := range p.Relays {
+		if p.RelayMarker(url) != marker {
+			relays = append(relays, url)
+		}
+	}
+	return relays
+}
+
+func relayKey(url string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(url), "/"))
+}
+
+func containsRelay(list []string, url string) bool {
+	for _, item := range list {
+		if relayKey(item) == relayKey(url) {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutRelay(list []string, url string) []string {
+	var kept []string
+	for _, item := range list {
+		if relayKey(item) != relayKey(url) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,28 @@
+package nostr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelayMarkers(t *testing.T) {
+	profile := &Profile{Relays: []string{"wss://both.example", "wss://inbox.example", "wss://outbox.example/"}}
+	profile.SetRelayMarker("wss://inbox.example", MarkerRead)
+	profile.SetRelayMarker("wss://outbox.example", MarkerRead)
+	profile.SetRelayMarker("WSS://OUTBOX.example/", MarkerWrite)
+
+	if marker := profile.RelayMarker("wss://outbox.example/"); marker != MarkerWrite {
+		t.Fatalf("expected the newest marker to win, got %q", marker)
+	}
+	if read := profile.ReadRelays(); !reflect.DeepEqual(read, []string{"wss://both.example", "wss://inbox.example"}) {
+		t.Fatalf("unexpected read relays %v", read)
+	}
+	if write := profile.WriteRelays(); !reflect.DeepEqual(write, []string{"wss://both.example", "wss://outbox.example/"}) {
+		t.Fatalf("unexpected write relays %v", write)
+	}
+
+	profile.SetRelayMarker("wss://inbox.example", "")
+	if len(profile.ReadOnlyRelays) != 0 || profile.RelayMarker("wss://inbox.example") != "" {
+		t.Fatalf("expected the marker to be cleared, got %v", profile.ReadOnlyRelays)
+	}
+}
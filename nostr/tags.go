@@ -0,0 +1,18 @@
+package nostr
+
+import nostrlib "github.com/nbd-wtf/go-nostr"
+
+// AppendUniqueTag appends tag unless tags already has one with the same key
+// and value. Unlike go-nostr's Tags.AppendUnique, values only match when they
+// are equal, so #nostr is kept next to #nostrdev and k=3 next to k=30023.
+func AppendUniqueTag(tags nostrlib.Tags, tag nostrlib.Tag) nostrlib.Tags {
+	if len(tag) < 2 {
+		return append(tags, tag)
+	}
+	for _, existing := range tags {
+		if len(existing) >= 2 && existing[0] == tag[0] && existing[1] == tag[1] {
+			return tags
+		}
+	}
+	return append(tags, tag)
+}